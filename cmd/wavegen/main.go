@@ -2,10 +2,13 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"io/ioutil"
-	"math"
+	"os"
 
+	"github.com/NeowayLabs/signal"
 	"github.com/NeowayLabs/signal/encoding/wave"
+	"github.com/NeowayLabs/signal/generator"
 )
 
 var (
@@ -14,6 +17,16 @@ var (
 	volume     uint
 	nsamples   uint
 	frequency  float64
+	duration   float64
+	phase      float64
+	waveform   string
+	duty       float64
+	toFreq     float64
+	sweep      string
+	digits     string
+	pause      float64
+	seed       int64
+	delay      float64
 )
 
 func init() {
@@ -22,28 +35,109 @@ func init() {
 		"The sample rate. Eg.: 8000, 44100, 48000, 96000, etc")
 	flag.UintVar(&volume, "volume", 28000, "volume range from 0 to 32767")
 	flag.UintVar(&nsamples, "nsamples", 1000, "Number of samples to generate")
-	flag.Float64Var(&frequency, "frequency", 440.0, "sine wave frequency")
+	flag.Float64Var(&frequency, "frequency", 440.0,
+		"frequency of the periodic signals (start frequency of chirps)")
+	flag.Float64Var(&duration, "duration", 0,
+		"duration in seconds (overrides nsamples, the duration of each DTMF digit)")
+	flag.Float64Var(&phase, "phase", 0, "initial phase, in radians")
+	flag.StringVar(&waveform, "signal", "sine",
+		"signal to generate: sine, square, sawtooth, triangle, pulse, chirp, "+
+			"dtmf, white, pink, brown, impulse or step")
+	flag.Float64Var(&duty, "duty", 0.5, "duty cycle of the pulse train, from 0 to 1")
+	flag.Float64Var(&toFreq, "to", 4000, "end frequency of chirps")
+	flag.StringVar(&sweep, "sweep", "linear", "sweep of chirps: linear or log")
+	flag.StringVar(&digits, "digits", "0123456789*#", "DTMF digits")
+	flag.Float64Var(&pause, "pause", 0.1, "pause between DTMF digits, in seconds")
+	flag.Int64Var(&seed, "seed", 1, "seed of the noises")
+	flag.Float64Var(&delay, "delay", 0, "delay of the impulse and step, in seconds")
+}
+
+func generate(p generator.Params) (signal.Discrete, error) {
+	switch waveform {
+	case "sine":
+		return generator.Sine(p, frequency)
+	case "square":
+		return generator.Square(p, frequency)
+	case "sawtooth":
+		return generator.Sawtooth(p, frequency)
+	case "triangle":
+		return generator.Triangle(p, frequency)
+	case "pulse":
+		return generator.Pulse(p, frequency, duty)
+	case "chirp":
+		switch sweep {
+		case "linear":
+			return generator.Chirp(p, frequency, toFreq, generator.LinearSweep)
+		case "log":
+			return generator.Chirp(p, frequency, toFreq, generator.LogSweep)
+		}
+		return nil, fmt.Errorf("unknown sweep: %s", sweep)
+	case "dtmf":
+		return dtmf(p)
+	case "white":
+		return generator.WhiteNoise(p, seed)
+	case "pink":
+		return generator.PinkNoise(p, seed)
+	case "brown":
+		return generator.BrownNoise(p, seed)
+	case "impulse":
+		return generator.Impulse(p, delay)
+	case "step":
+		return generator.Step(p, delay)
+	}
+	return nil, fmt.Errorf("unknown signal: %s", waveform)
+}
+
+// dtmf generates the DTMF digits, each one lasting the duration of p,
+// separated by pauses.
+func dtmf(p generator.Params) (signal.Discrete, error) {
+	silence := make(signal.Discrete, int(pause*p.SampleRate+0.5))
+
+	var sig signal.Discrete
+	for i, d := range digits {
+		if i > 0 {
+			sig = append(sig, silence...)
+		}
+		tone, err := generator.DTMF(p, d)
+		if err != nil {
+			return nil, err
+		}
+		sig = append(sig, tone...)
+	}
+	return sig, nil
 }
 
 func main() {
 	flag.Parse()
-	var freqRadPerSample = frequency * 2 * math.Pi / float64(sampleRate)
-	var phase float64
-	enc := wave.NewEncoder(wave.NewPCM(1, int(sampleRate), 16))
 
-	var data []int16
+	err := run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+}
 
-	for i := uint(0); i < nsamples; i++ {
-		phase += freqRadPerSample
-		sample := float64(volume) * math.Sin(phase)
-		data = append(data, int16(sample))
+func run() error {
+	if duration <= 0 {
+		duration = float64(nsamples) / float64(sampleRate)
 	}
-	audioBytes, err := enc.EncodeInt16(data)
+
+	// the same scale the encoder quantizes the samples with, so the
+	// volume is the peak of the 16 bits samples
+	sig, err := generate(generator.Params{
+		SampleRate: float64(sampleRate),
+		Duration:   duration,
+		Amplitude:  float64(volume) / (1 << 15),
+		Phase:      phase,
+	})
 	if err != nil {
-		panic(err)
+		return err
 	}
-	err = ioutil.WriteFile(filename, audioBytes, 0664)
+
+	enc := wave.NewEncoder(wave.NewPCM(1, int(sampleRate), 16))
+	audioBytes, err := enc.EncodeDiscrete([]signal.Discrete{sig})
 	if err != nil {
-		panic(err)
+		return err
 	}
+	return ioutil.WriteFile(filename, audioBytes, 0664)
 }
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/NeowayLabs/signal/encoding/wave"
+)
+
+var (
+	input  string
+	output string
+	dryRun bool
+)
+
+func init() {
+	flag.StringVar(&input, "input", "", "corrupted WAV file")
+	flag.StringVar(&output, "output", "repaired.wav", "repaired WAV file")
+	flag.BoolVar(&dryRun, "dryrun", false, "only report the changes, without writing the output")
+}
+
+func main() {
+	flag.Parse()
+
+	err := run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	if input == "" {
+		return fmt.Errorf("no input file (see -input)")
+	}
+
+	f, err := os.Open(input)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var repaired bytes.Buffer
+	report, err := wave.Repair(f, &repaired)
+	if err != nil {
+		return err
+	}
+
+	if len(report.Changes) == 0 {
+		fmt.Printf("%s: no changes needed\n", input)
+	}
+	for _, change := range report.Changes {
+		fmt.Printf("%s: %s\n", input, change)
+	}
+
+	if dryRun {
+		return nil
+	}
+	return ioutil.WriteFile(output, repaired.Bytes(), 0664)
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"github.com/NeowayLabs/signal"
+)
+
+const (
+	marginLeft   = 56
+	marginBottom = 28
+	marginTop    = 8
+	marginRight  = 16
+
+	tickSize   = 4
+	fontScale  = 2
+	minSpacing = 16 // minimum pixels between the ticks
+)
+
+var (
+	background = color.RGBA{0, 0, 0, 255}
+	foreground = color.RGBA{255, 255, 255, 255}
+)
+
+// glyphs is a 3x5 bitmap font with the characters of the labels,
+// one row per string.
+var glyphs = map[rune][5]string{
+	'0': {"###", "#.#", "#.#", "#.#", "###"},
+	'1': {".#.", "##.", ".#.", ".#.", "###"},
+	'2': {"###", "..#", "###", "#..", "###"},
+	'3': {"###", "..#", "###", "..#", "###"},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "###", "..#", "###"},
+	'6': {"###", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", "..#", ".#.", ".#."},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+	'9': {"###", "#.#", "###", "..#", "###"},
+	'.': {"...", "...", "...", "...", ".#."},
+	'k': {"#..", "#.#", "##.", "#.#", "#.#"},
+	's': {"...", ".##", "##.", "..#", "##."},
+}
+
+// drawAxes returns a new image with the spectrogram surrounded by the
+// time axis (bottom) and the frequency axis (left).
+func drawAxes(spec *image.RGBA, sp *signal.Spectrogram, freq func(float64) float64) *image.RGBA {
+	w, h := spec.Bounds().Dx(), spec.Bounds().Dy()
+	img := image.NewRGBA(image.Rect(0, 0, marginLeft+w+marginRight, marginTop+h+marginBottom))
+	draw.Draw(img, img.Bounds(), &image.Uniform{background}, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(marginLeft, marginTop, marginLeft+w, marginTop+h), spec, image.Point{}, draw.Src)
+
+	// axis lines
+	for x := marginLeft - 1; x < marginLeft+w; x++ {
+		img.Set(x, marginTop+h, foreground)
+	}
+	for y := marginTop; y <= marginTop+h; y++ {
+		img.Set(marginLeft-1, y, foreground)
+	}
+
+	// frequency ticks
+	top := freq(1)
+	last := math.Inf(1)
+	for _, f := range frequencyTicks(top) {
+		y := marginTop + int(float64(h)*(1-position(freq, f))+0.5)
+		if y < marginTop || y > marginTop+h || last-float64(y) < minSpacing {
+			continue
+		}
+		last = float64(y)
+
+		for x := marginLeft - 1 - tickSize; x < marginLeft-1; x++ {
+			img.Set(x, y, foreground)
+		}
+		label := formatFrequency(f)
+		drawText(img, label, marginLeft-tickSize-4-textWidth(label), y-5*fontScale/2)
+	}
+
+	// time ticks
+	hopTime := float64(sp.Hop) / sp.SampleRate
+	duration := float64(w) * hopTime
+	nticks := w / (40 * fontScale / 2)
+	if nticks < 1 {
+		nticks = 1
+	}
+	step := niceStep(duration / float64(nticks))
+	for t := 0.0; t <= duration; t += step {
+		x := marginLeft + int(t/hopTime+0.5)
+		for y := marginTop + h + 1; y <= marginTop+h+tickSize; y++ {
+			img.Set(x, y, foreground)
+		}
+		label := fixed(roundTo(t, step)) + "s"
+		drawText(img, label, x-textWidth(label)/2, marginTop+h+tickSize+4)
+	}
+	return img
+}
+
+// frequencyTicks returns the candidate frequencies of the ticks up to
+// top hertz: multiples of a nice step for the linear scale, and the
+// 1-2-5 series from 10Hz for the log and mel scales.
+func frequencyTicks(top float64) []float64 {
+	if scale == "linear" {
+		step := niceStep(top / float64(height/(2*minSpacing)+1))
+		var ticks []float64
+		for f := 0.0; f <= top; f += step {
+			ticks = append(ticks, roundTo(f, step))
+		}
+		return ticks
+	}
+
+	var ticks []float64
+	if scale == "mel" {
+		ticks = append(ticks, 0)
+	}
+	for decade := 10.0; decade <= top; decade *= 10 {
+		for _, m := range []float64{1, 2, 5} {
+			if f := m * decade; f <= top {
+				ticks = append(ticks, f)
+			}
+		}
+	}
+	return ticks
+}
+
+// position finds the vertical position (0 to 1) of the frequency f by
+// bisection, because the frequency scales are monotonic.
+func position(freq func(float64) float64, f float64) float64 {
+	lo, hi := 0.0, 1.0
+	if f < freq(lo) {
+		return -1
+	}
+	for i := 0; i < 50; i++ {
+		mid := (lo + hi) / 2
+		if freq(mid) < f {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// niceStep rounds x up to 1, 2 or 5 times a power of ten.
+func niceStep(x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	p := math.Pow(10, math.Floor(math.Log10(x)))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if m*p >= x {
+			return m * p
+		}
+	}
+	return 10 * p
+}
+
+// roundTo removes the accumulated round-off of the multiples of step.
+func roundTo(x, step float64) float64 {
+	return math.Floor(x/step+0.5) * step
+}
+
+func formatFrequency(f float64) string {
+	if f < 1000 {
+		return fixed(f)
+	}
+	return fixed(f/1000) + "k"
+}
+
+// fixed formats x in fixed point with the fewest decimals needed, up to
+// three, because the font has no glyphs for the exponent of %g.
+func fixed(x float64) string {
+	decimals := 0
+	for ; decimals < 3; decimals++ {
+		scaled := x * math.Pow(10, float64(decimals))
+		if math.Abs(scaled-math.Floor(scaled+0.5)) < 1e-6 {
+			break
+		}
+	}
+	return fmt.Sprintf("%.*f", decimals, x)
+}
+
+func textWidth(text string) int {
+	return len(text) * 4 * fontScale
+}
+
+// drawText draws the text with its top left corner at (x, y).
+func drawText(img *image.RGBA, text string, x, y int) {
+	for _, r := range text {
+		glyph := glyphs[r]
+		for row, line := range glyph {
+			for col, c := range line {
+				if c != '#' {
+					continue
+				}
+				for dy := 0; dy < fontScale; dy++ {
+					for dx := 0; dx < fontScale; dx++ {
+						img.Set(x+col*fontScale+dx, y+row*fontScale+dy, foreground)
+					}
+				}
+			}
+		}
+		x += 4 * fontScale
+	}
+}
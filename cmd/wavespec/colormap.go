@@ -0,0 +1,56 @@
+package main
+
+import (
+	"image/color"
+	"math"
+)
+
+// colormap maps the values from 0 to 1 into colors, interpolating
+// linearly between the color stops spread evenly over the range.
+type colormap []color.RGBA
+
+var colormaps = map[string]colormap{
+	"gray": {
+		{0, 0, 0, 255}, {255, 255, 255, 255},
+	},
+	"hot": {
+		{0, 0, 0, 255}, {230, 0, 0, 255}, {255, 210, 0, 255}, {255, 255, 255, 255},
+	},
+	"jet": {
+		{0, 0, 143, 255}, {0, 0, 255, 255}, {0, 255, 255, 255},
+		{255, 255, 0, 255}, {255, 0, 0, 255}, {128, 0, 0, 255},
+	},
+	"viridis": {
+		{68, 1, 84, 255}, {72, 40, 120, 255}, {62, 73, 137, 255},
+		{49, 104, 142, 255}, {38, 130, 142, 255}, {31, 158, 137, 255},
+		{53, 183, 121, 255}, {110, 206, 88, 255}, {181, 222, 43, 255},
+		{253, 231, 37, 255},
+	},
+}
+
+// at returns the color of v, that is clipped into [0, 1].
+func (c colormap) at(v float64) color.RGBA {
+	if math.IsNaN(v) || v < 0 {
+		v = 0
+	}
+	if v > 1 {
+		v = 1
+	}
+
+	pos := v * float64(len(c)-1)
+	i := int(pos)
+	if i >= len(c)-1 {
+		return c[len(c)-1]
+	}
+
+	frac := pos - float64(i)
+	mix := func(a, b uint8) uint8 {
+		return uint8(float64(a) + frac*(float64(b)-float64(a)) + 0.5)
+	}
+	return color.RGBA{
+		R: mix(c[i].R, c[i+1].R),
+		G: mix(c[i].G, c[i+1].G),
+		B: mix(c[i].B, c[i+1].B),
+		A: 255,
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/png"
+	"math"
+	"os"
+
+	"github.com/NeowayLabs/signal"
+	"github.com/NeowayLabs/signal/encoding/wave"
+)
+
+var (
+	input     string
+	output    string
+	channel   int
+	frameSize int
+	hop       int
+	fftSize   int
+	window    string
+	cmap      string
+	dbMin     float64
+	dbMax     float64
+	scale     string
+	minFreq   float64
+	height    int
+	axes      bool
+)
+
+var windows = map[string]signal.Window{
+	"rectangular":    signal.Rectangular,
+	"hann":           signal.Hann,
+	"hamming":        signal.Hamming,
+	"blackman":       signal.Blackman,
+	"blackmanharris": signal.BlackmanHarris,
+	"flattop":        signal.FlatTop,
+}
+
+func init() {
+	flag.StringVar(&input, "input", "", "input WAV file")
+	flag.StringVar(&output, "output", "out.png", "output PNG file")
+	flag.IntVar(&channel, "channel", -1, "channel to analyze (-1 mixes all channels)")
+	flag.IntVar(&frameSize, "frame", 512, "samples per STFT frame")
+	flag.IntVar(&hop, "hop", 128, "samples between the STFT frames")
+	flag.IntVar(&fftSize, "fftsize", 0, "zero-padded FFT size (0 uses the frame size)")
+	flag.StringVar(&window, "window", "hann",
+		"window: rectangular, hann, hamming, blackman, blackmanharris or flattop")
+	flag.StringVar(&cmap, "colormap", "viridis", "color map: gray, hot, jet or viridis")
+	flag.Float64Var(&dbMin, "dbmin", -100, "bottom of the dB range (dBFS)")
+	flag.Float64Var(&dbMax, "dbmax", 0, "top of the dB range (dBFS)")
+	flag.StringVar(&scale, "scale", "linear", "frequency scale: linear, log or mel")
+	flag.Float64Var(&minFreq, "minfreq", 20, "lowest frequency of the log scale, in hertz")
+	flag.IntVar(&height, "height", 512, "height of the spectrogram, in pixels")
+	flag.BoolVar(&axes, "axes", false, "draw the time and frequency axes")
+}
+
+func main() {
+	flag.Parse()
+
+	err := run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	if input == "" {
+		return fmt.Errorf("no input file (see -input)")
+	}
+	if dbMin >= dbMax {
+		return fmt.Errorf("invalid dB range: [%f, %f]", dbMin, dbMax)
+	}
+	if frameSize < 2 {
+		return fmt.Errorf("invalid frame size: %d", frameSize)
+	}
+	if height <= 0 {
+		return fmt.Errorf("invalid height: %d", height)
+	}
+
+	win, ok := windows[window]
+	if !ok {
+		return fmt.Errorf("unknown window: %s", window)
+	}
+	colors, ok := colormaps[cmap]
+	if !ok {
+		return fmt.Errorf("unknown color map: %s", cmap)
+	}
+
+	sig, samplerate, err := load()
+	if err != nil {
+		return err
+	}
+
+	sp, err := signal.STFT{
+		FrameSize:  frameSize,
+		Hop:        hop,
+		FFTSize:    fftSize,
+		Window:     win,
+		SampleRate: samplerate,
+	}.Transform(sig)
+	if err != nil {
+		return err
+	}
+	if len(sp.Frames) == 0 {
+		return fmt.Errorf("no audio samples in %s", input)
+	}
+
+	freq, err := frequencyScale(scale, samplerate)
+	if err != nil {
+		return err
+	}
+
+	img := render(sp, win(frameSize), freq, colors)
+	if axes {
+		img = drawAxes(img, sp, freq)
+	}
+
+	out, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	return png.Encode(out, img)
+}
+
+// load decodes the input file, returning the analyzed channel.
+func load() (signal.Discrete, float64, error) {
+	f, err := os.Open(input)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	var m signal.Multichannel
+	_, err = wave.NewDecoder(f).DecodeMultichannel(&m)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if channel >= m.NumChannels() {
+		return nil, 0, fmt.Errorf("invalid channel %d, the file has %d channels",
+			channel, m.NumChannels())
+	}
+	if channel >= 0 {
+		return m.Channels[channel], m.SampleRate, nil
+	}
+
+	// mixes the channels down to mono
+	mono := make(signal.Discrete, m.NumFrames())
+	for _, ch := range m.Channels {
+		for i := range mono {
+			mono[i] += ch[i] / float64(m.NumChannels())
+		}
+	}
+	return mono, m.SampleRate, nil
+}
+
+// frequencyScale returns the function mapping the vertical position
+// (from 0 at the bottom to 1 at the top) to the frequency in hertz.
+func frequencyScale(name string, samplerate float64) (func(pos float64) float64, error) {
+	nyquist := samplerate / 2
+
+	switch name {
+	case "linear":
+		return func(pos float64) float64 {
+			return pos * nyquist
+		}, nil
+	case "log":
+		if minFreq <= 0 || minFreq >= nyquist {
+			return nil, fmt.Errorf("invalid minimum frequency: %f", minFreq)
+		}
+		ratio := nyquist / minFreq
+		return func(pos float64) float64 {
+			return minFreq * math.Pow(ratio, pos)
+		}, nil
+	case "mel":
+		top := mel(nyquist)
+		return func(pos float64) float64 {
+			return invMel(pos * top)
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown frequency scale: %s", name)
+}
+
+func mel(f float64) float64 {
+	return 2595 * math.Log10(1+f/700)
+}
+
+func invMel(m float64) float64 {
+	return 700 * (math.Pow(10, m/2595) - 1)
+}
+
+// render draws one column per frame of the spectrogram, with the
+// magnitudes in dBFS mapped into the color map.
+func render(sp *signal.Spectrogram, win signal.Discrete, freq func(float64) float64, colors colormap) *image.RGBA {
+	// a full scale sinusoid is 0dBFS
+	var wsum float64
+	for _, v := range win {
+		wsum += v
+	}
+	norm := 2 / wsum
+
+	binWidth := sp.SampleRate / float64(sp.FFTSize)
+	img := image.NewRGBA(image.Rect(0, 0, len(sp.Frames), height))
+
+	for x, mags := range sp.Magnitudes() {
+		for y := 0; y < height; y++ {
+			pos := 1 - (float64(y)+0.5)/float64(height)
+
+			// interpolates between the nearest bins
+			k := freq(pos) / binWidth
+			i := int(k)
+			if i >= len(mags)-1 {
+				i, k = len(mags)-2, float64(len(mags)-1)
+			}
+			frac := k - float64(i)
+			mag := (mags[i]*(1-frac) + mags[i+1]*frac) * norm
+
+			db := 20 * math.Log10(mag)
+			img.Set(x, y, colors.at((db-dbMin)/(dbMax-dbMin)))
+		}
+	}
+	return img
+}
@@ -0,0 +1,242 @@
+package signal
+
+import "math"
+
+// Normalization of the correlation.
+type Normalization int
+
+const (
+	// NormNone doesn't normalize the correlation.
+	NormNone Normalization = iota
+	// NormBiased divides every lag by the length of the signals.
+	NormBiased
+	// NormUnbiased divides every lag by the number of overlapping
+	// samples at that lag.
+	NormUnbiased
+	// NormCoeff normalizes the correlation such that the
+	// autocorrelation at lag zero is 1 (correlation coefficient).
+	NormCoeff
+)
+
+// directLimit is the kernel length up to which the direct convolution
+// is faster than the FFT convolution.
+const directLimit = 64
+
+// Convolve calculates the convolution of the signal x with the
+// impulse response h using the input side algorithm (DSP Book,
+// Chapter 6). The output has len(x)+len(h)-1 samples.
+// y(i) = Σx(j)·h(i-j)
+func Convolve(x, h Discrete) Discrete {
+	if len(x) == 0 || len(h) == 0 {
+		return Discrete{}
+	}
+
+	y := make(Discrete, len(x)+len(h)-1)
+	for i, xv := range x {
+		for j, hv := range h {
+			y[i+j] += xv * hv
+		}
+	}
+	return y
+}
+
+// FFTConvolve calculates the convolution of x and h multiplying their
+// spectrums (DSP Book, Chapter 18). It gives the same result of
+// Convolve, but is faster for long impulse responses.
+func FFTConvolve(x, h Discrete) Discrete {
+	if len(x) == 0 || len(h) == 0 {
+		return Discrete{}
+	}
+
+	n := len(x) + len(h) - 1
+	size := nextPowerOf2(n)
+
+	X := fftPadded(x, size)
+	H := fftPadded(h, size)
+	for i := range X {
+		X[i] *= H[i]
+	}
+	radix2(X, true)
+
+	y := make(Discrete, n)
+	for i := range y {
+		y[i] = real(X[i]) / float64(size)
+	}
+	return y
+}
+
+// FastConvolve calculates the convolution of x and h using the
+// fastest method for the length of the signals.
+func FastConvolve(x, h Discrete) Discrete {
+	if len(x) <= directLimit || len(h) <= directLimit {
+		return Convolve(x, h)
+	}
+	if len(x) > 4*len(h) {
+		return OverlapAdd(x, h, 0)
+	}
+	return FFTConvolve(x, h)
+}
+
+// OverlapAdd calculates the convolution of the long signal x with h
+// breaking x into blocks of blocksize samples, that are convolved
+// using the FFT and overlap added into the output (DSP Book, Chapter
+// 18). If blocksize is zero, a block size is chosen based on the
+// length of h. The output is the same of Convolve.
+func OverlapAdd(x, h Discrete, blocksize int) Discrete {
+	if len(x) == 0 || len(h) == 0 {
+		return Discrete{}
+	}
+	if blocksize <= 0 {
+		blocksize = defaultBlockSize(len(h))
+	}
+
+	size := nextPowerOf2(blocksize + len(h) - 1)
+	H := fftPadded(h, size)
+
+	y := make(Discrete, len(x)+len(h)-1)
+	for start := 0; start < len(x); start += blocksize {
+		end := start + blocksize
+		if end > len(x) {
+			end = len(x)
+		}
+
+		X := fftPadded(x[start:end], size)
+		for i := range X {
+			X[i] *= H[i]
+		}
+		radix2(X, true)
+
+		for i := 0; i < end-start+len(h)-1; i++ {
+			y[start+i] += real(X[i]) / float64(size)
+		}
+	}
+	return y
+}
+
+// OverlapSave calculates the convolution of the long signal x with h
+// using overlapping input segments of the FFT size and discarding the
+// samples affected by the circular convolution. Each segment
+// produces at least blocksize new samples (if blocksize is zero, it's
+// chosen based on the length of h). The output is the same of
+// Convolve.
+func OverlapSave(x, h Discrete, blocksize int) Discrete {
+	if len(x) == 0 || len(h) == 0 {
+		return Discrete{}
+	}
+	if blocksize <= 0 {
+		blocksize = defaultBlockSize(len(h))
+	}
+
+	m := len(h)
+	size := nextPowerOf2(blocksize + m - 1)
+	step := size - m + 1
+	H := fftPadded(h, size)
+
+	n := len(x) + m - 1
+	// pads the input with m-1 zeros at the start, that are the
+	// history of the first segment
+	padded := make(Discrete, m-1+n+size)
+	copy(padded[m-1:], x)
+
+	y := make(Discrete, n)
+	for start := 0; start < n; start += step {
+		X := fftPadded(padded[start:start+size], size)
+		for i := range X {
+			X[i] *= H[i]
+		}
+		radix2(X, true)
+
+		for i := 0; i < step && start+i < n; i++ {
+			y[start+i] = real(X[m-1+i]) / float64(size)
+		}
+	}
+	return y
+}
+
+// Correlate calculates the cross-correlation of x and y, normalized
+// by norm (DSP Book, Chapter 7). The output has len(x)+len(y)-1
+// samples, where the sample i is the correlation at lag
+// i-(len(y)-1), ie. the lag zero is at index len(y)-1.
+// r(l) = Σx(n+l)·y(n)
+func Correlate(x, y Discrete, norm Normalization) Discrete {
+	if len(x) == 0 || len(y) == 0 {
+		return Discrete{}
+	}
+
+	reversed := make(Discrete, len(y))
+	for i, v := range y {
+		reversed[len(y)-1-i] = v
+	}
+
+	r := FastConvolve(x, reversed)
+
+	switch norm {
+	case NormBiased:
+		n := float64(len(x))
+		if len(y) > len(x) {
+			n = float64(len(y))
+		}
+		for i := range r {
+			r[i] /= n
+		}
+	case NormUnbiased:
+		for i := range r {
+			lag := i - (len(y) - 1)
+			r[i] /= float64(overlap(len(x), len(y), lag))
+		}
+	case NormCoeff:
+		scale := math.Sqrt(energy(x) * energy(y))
+		if scale == 0 {
+			break
+		}
+		for i := range r {
+			r[i] /= scale
+		}
+	}
+	return r
+}
+
+// AutoCorrelate calculates the correlation of x with itself. The
+// output has 2*len(x)-1 samples with the lag zero at index len(x)-1.
+func AutoCorrelate(x Discrete, norm Normalization) Discrete {
+	return Correlate(x, x, norm)
+}
+
+// overlap returns the number of samples n where 0 <= n < m and
+// 0 <= n+lag < nx.
+func overlap(nx, m, lag int) int {
+	lo := 0
+	if lag < 0 {
+		lo = -lag
+	}
+	hi := m
+	if nx-lag < hi {
+		hi = nx - lag
+	}
+	return hi - lo
+}
+
+func energy(x Discrete) float64 {
+	var sum float64
+	for _, v := range x {
+		sum += v * v
+	}
+	return sum
+}
+
+// defaultBlockSize returns a block size for the FFT convolution with
+// a kernel of m samples.
+func defaultBlockSize(m int) int {
+	return nextPowerOf2(4*m) - m + 1
+}
+
+// fftPadded returns the FFT of x padded with zeros up to size samples,
+// that must be a power of two.
+func fftPadded(x Discrete, size int) []complex128 {
+	X := make([]complex128, size)
+	for i, v := range x {
+		X[i] = complex(v, 0)
+	}
+	radix2(X, false)
+	return X
+}
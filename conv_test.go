@@ -0,0 +1,98 @@
+package signal_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/NeowayLabs/signal"
+)
+
+func testSignal(n int) signal.Discrete {
+	sig := make(signal.Discrete, n)
+	for i := range sig {
+		sig[i] = math.Sin(float64(i)*0.3) + 0.5*math.Cos(float64(i)*1.7)
+	}
+	return sig
+}
+
+func assertDiscreteAlmost(t *testing.T, got, expected signal.Discrete, ε float64, msg string) {
+	t.Helper()
+	assert(t, len(got) == len(expected), fmt("%s: length differs: %d != %d",
+		msg, len(got), len(expected)))
+	for i := range got {
+		assertAlmost(t, got[i], expected[i], ε, fmt("%s: sample[%d]", msg, i))
+	}
+}
+
+func TestConvolve(t *testing.T) {
+	got := signal.Convolve(signal.Discrete{1, 2, 3}, signal.Discrete{0, 1, 0.5})
+	assertDiscreteAlmost(t, got, signal.Discrete{0, 1, 2.5, 4, 1.5}, precision, "convolve")
+
+	// the delta function is the identity of convolution
+	x := testSignal(10)
+	assertDiscreteAlmost(t, signal.Convolve(x, signal.Discrete{1}), x, precision, "delta")
+
+	assert(t, len(signal.Convolve(nil, x)) == 0, "empty convolution")
+}
+
+func TestFastConvolutions(t *testing.T) {
+	x := testSignal(1000)
+	for _, m := range []int{1, 3, 31, 100, 257} {
+		h := testSignal(m)
+		expected := signal.Convolve(x, h)
+
+		assertDiscreteAlmost(t, signal.FFTConvolve(x, h), expected, 1e-9,
+			fmt("fft convolve m=%d", m))
+		assertDiscreteAlmost(t, signal.FastConvolve(x, h), expected, 1e-9,
+			fmt("fast convolve m=%d", m))
+
+		for _, blocksz := range []int{0, 1, 64, 500, 2000} {
+			assertDiscreteAlmost(t, signal.OverlapAdd(x, h, blocksz), expected, 1e-9,
+				fmt("overlap-add m=%d block=%d", m, blocksz))
+			assertDiscreteAlmost(t, signal.OverlapSave(x, h, blocksz), expected, 1e-9,
+				fmt("overlap-save m=%d block=%d", m, blocksz))
+		}
+	}
+}
+
+func TestCorrelate(t *testing.T) {
+	x := signal.Discrete{1, 2, 3}
+	y := signal.Discrete{0, 1}
+
+	// lags -1, 0, 1, 2
+	assertDiscreteAlmost(t, signal.Correlate(x, y, signal.NormNone),
+		signal.Discrete{1, 2, 3, 0}, precision, "correlation")
+	assertDiscreteAlmost(t, signal.Correlate(x, y, signal.NormBiased),
+		signal.Discrete{1.0 / 3, 2.0 / 3, 1, 0}, precision, "biased correlation")
+	assertDiscreteAlmost(t, signal.Correlate(x, y, signal.NormUnbiased),
+		signal.Discrete{1, 1, 1.5, 0}, precision, "unbiased correlation")
+}
+
+func TestCorrelateDelay(t *testing.T) {
+	const delay = 37
+
+	x := testSignal(500)
+	delayed := make(signal.Discrete, len(x))
+	copy(delayed[delay:], x)
+
+	r := signal.Correlate(delayed, x, signal.NormCoeff)
+	peak := 0
+	for i := range r {
+		if r[i] > r[peak] {
+			peak = i
+		}
+	}
+	assert(t, peak-(len(x)-1) == delay, fmt("wrong delay: %d", peak-(len(x)-1)))
+}
+
+func TestAutoCorrelate(t *testing.T) {
+	x := testSignal(300)
+	r := signal.AutoCorrelate(x, signal.NormCoeff)
+
+	assert(t, len(r) == 2*len(x)-1, "autocorrelation length")
+	assertAlmost(t, r[len(x)-1], 1, precision, "autocorrelation at lag zero")
+	for i := range r {
+		assertAlmost(t, r[i], r[len(r)-1-i], 1e-9, "autocorrelation symmetry")
+		assert(t, r[i] <= 1+precision, "autocorrelation coefficient above 1")
+	}
+}
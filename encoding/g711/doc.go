@@ -0,0 +1,5 @@
+// Package g711 implements the A-law and μ-law companding algorithms
+// of the ITU-T G.711 recommendation, used mostly in telephony.
+// The specification could be found here:
+//   https://www.itu.int/rec/T-REC-G.711
+package g711
@@ -0,0 +1,167 @@
+package g711
+
+const (
+	signBit   = 0x80 // sign bit of a companded sample
+	quantMask = 0x0F // quantization field mask
+	segShift  = 4    // segment field shift
+	segMask   = 0x70 // segment field mask
+
+	ulawBias = 0x84 // bias for linear code
+	ulawClip = 8159 // max value of 14 bit magnitude
+)
+
+var (
+	// end of the segments of 13 bit (A-law) and 14 bit (μ-law) input
+	alawSegEnd = [8]int{0x1F, 0x3F, 0x7F, 0xFF, 0x1FF, 0x3FF, 0x7FF, 0xFFF}
+	ulawSegEnd = [8]int{0x3F, 0x7F, 0xFF, 0x1FF, 0x3FF, 0x7FF, 0xFFF, 0x1FFF}
+
+	alawTable [256]int16 // A-law to linear
+	ulawTable [256]int16 // μ-law to linear
+)
+
+func init() {
+	for i := range alawTable {
+		alawTable[i] = alaw2linear(uint8(i))
+		ulawTable[i] = ulaw2linear(uint8(i))
+	}
+}
+
+func segment(val int, segEnd *[8]int) int {
+	for i, end := range segEnd {
+		if val <= end {
+			return i
+		}
+	}
+	return len(segEnd)
+}
+
+// EncodeALaw compresses a 16 bit linear PCM sample into A-law.
+func EncodeALaw(pcm int16) uint8 {
+	var mask uint8
+	val := int(pcm) >> 3
+	if val >= 0 {
+		mask = 0xD5 // sign (7th) bit = 1
+	} else {
+		mask = 0x55 // sign bit = 0
+		val = -val - 1
+	}
+
+	seg := segment(val, &alawSegEnd)
+	if seg >= 8 {
+		// out of range, return maximum value
+		return 0x7F ^ mask
+	}
+
+	aval := uint8(seg << segShift)
+	if seg < 2 {
+		aval |= uint8(val>>1) & quantMask
+	} else {
+		aval |= uint8(val>>uint(seg)) & quantMask
+	}
+	return aval ^ mask
+}
+
+// DecodeALaw expands an A-law sample into 16 bit linear PCM.
+func DecodeALaw(alaw uint8) int16 {
+	return alawTable[alaw]
+}
+
+func alaw2linear(alaw uint8) int16 {
+	alaw ^= 0x55
+
+	t := int(alaw&quantMask) << 4
+	seg := uint(alaw&segMask) >> segShift
+	switch seg {
+	case 0:
+		t += 8
+	case 1:
+		t += 0x108
+	default:
+		t += 0x108
+		t <<= seg - 1
+	}
+
+	if alaw&signBit != 0 {
+		return int16(t)
+	}
+	return int16(-t)
+}
+
+// EncodeMuLaw compresses a 16 bit linear PCM sample into μ-law.
+func EncodeMuLaw(pcm int16) uint8 {
+	var mask uint8
+	val := int(pcm) >> 2
+	if val < 0 {
+		val = -val
+		mask = 0x7F
+	} else {
+		mask = 0xFF
+	}
+
+	if val > ulawClip {
+		val = ulawClip
+	}
+	val += ulawBias >> 2
+
+	seg := segment(val, &ulawSegEnd)
+	if seg >= 8 {
+		// out of range, return maximum value
+		return 0x7F ^ mask
+	}
+
+	uval := uint8(seg<<segShift) | uint8(val>>uint(seg+1))&quantMask
+	return uval ^ mask
+}
+
+// DecodeMuLaw expands a μ-law sample into 16 bit linear PCM.
+func DecodeMuLaw(ulaw uint8) int16 {
+	return ulawTable[ulaw]
+}
+
+func ulaw2linear(ulaw uint8) int16 {
+	ulaw = ^ulaw
+
+	t := (int(ulaw&quantMask) << 3) + ulawBias
+	t <<= uint(ulaw&segMask) >> segShift
+
+	if ulaw&signBit != 0 {
+		return int16(ulawBias - t)
+	}
+	return int16(t - ulawBias)
+}
+
+// CompressALaw compresses the linear PCM samples into A-law.
+func CompressALaw(pcm []int16) []uint8 {
+	out := make([]uint8, len(pcm))
+	for i, v := range pcm {
+		out[i] = EncodeALaw(v)
+	}
+	return out
+}
+
+// ExpandALaw expands the A-law samples into linear PCM.
+func ExpandALaw(alaw []uint8) []int16 {
+	out := make([]int16, len(alaw))
+	for i, v := range alaw {
+		out[i] = alawTable[v]
+	}
+	return out
+}
+
+// CompressMuLaw compresses the linear PCM samples into μ-law.
+func CompressMuLaw(pcm []int16) []uint8 {
+	out := make([]uint8, len(pcm))
+	for i, v := range pcm {
+		out[i] = EncodeMuLaw(v)
+	}
+	return out
+}
+
+// ExpandMuLaw expands the μ-law samples into linear PCM.
+func ExpandMuLaw(ulaw []uint8) []int16 {
+	out := make([]int16, len(ulaw))
+	for i, v := range ulaw {
+		out[i] = ulawTable[v]
+	}
+	return out
+}
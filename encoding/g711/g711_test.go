@@ -0,0 +1,104 @@
+package g711_test
+
+import (
+	"testing"
+
+	"github.com/NeowayLabs/signal/encoding/g711"
+)
+
+func TestALawKnownValues(t *testing.T) {
+	for _, tc := range []struct {
+		pcm  int16
+		alaw uint8
+	}{
+		{0, 0xD5},
+		{-1, 0x55},
+		{32767, 0xAA},
+		{-32768, 0x2A},
+		{1000, 0xFA},
+	} {
+		if got := g711.EncodeALaw(tc.pcm); got != tc.alaw {
+			t.Fatalf("EncodeALaw(%d): %#x != %#x", tc.pcm, got, tc.alaw)
+		}
+	}
+}
+
+func TestMuLawKnownValues(t *testing.T) {
+	for _, tc := range []struct {
+		pcm  int16
+		ulaw uint8
+	}{
+		{0, 0xFF},
+		{-1, 0x7E},
+		{32767, 0x80},
+		{-32768, 0x00},
+		{1000, 0xCE},
+	} {
+		if got := g711.EncodeMuLaw(tc.pcm); got != tc.ulaw {
+			t.Fatalf("EncodeMuLaw(%d): %#x != %#x", tc.pcm, got, tc.ulaw)
+		}
+	}
+}
+
+func TestALawRoundTrip(t *testing.T) {
+	for i := 0; i < 256; i++ {
+		code := uint8(i)
+		if got := g711.EncodeALaw(g711.DecodeALaw(code)); got != code {
+			t.Fatalf("code %#x: round trip gives %#x", code, got)
+		}
+	}
+}
+
+func TestMuLawRoundTrip(t *testing.T) {
+	for i := 0; i < 256; i++ {
+		code := uint8(i)
+		if code == 0x7F {
+			// negative zero is encoded as positive zero
+			continue
+		}
+		if got := g711.EncodeMuLaw(g711.DecodeMuLaw(code)); got != code {
+			t.Fatalf("code %#x: round trip gives %#x", code, got)
+		}
+	}
+}
+
+func TestCompandingError(t *testing.T) {
+	// the quantization error grows with the magnitude but must
+	// stay within the segment step
+	for pcm := -32768; pcm <= 32767; pcm += 7 {
+		v := int16(pcm)
+		step := int(abs(v))/16 + 16
+
+		if diff := abs(int16(int(g711.DecodeALaw(g711.EncodeALaw(v))) - pcm)); int(diff) > step {
+			t.Fatalf("A-law error too high for %d: %d", pcm, diff)
+		}
+		if diff := abs(int16(int(g711.DecodeMuLaw(g711.EncodeMuLaw(v))) - pcm)); int(diff) > step {
+			t.Fatalf("μ-law error too high for %d: %d", pcm, diff)
+		}
+	}
+}
+
+func TestSliceHelpers(t *testing.T) {
+	pcm := []int16{-1000, 0, 1000}
+
+	alaw := g711.CompressALaw(pcm)
+	for i, v := range g711.ExpandALaw(alaw) {
+		if v != g711.DecodeALaw(g711.EncodeALaw(pcm[i])) {
+			t.Fatalf("A-law sample %d differs", i)
+		}
+	}
+
+	ulaw := g711.CompressMuLaw(pcm)
+	for i, v := range g711.ExpandMuLaw(ulaw) {
+		if v != g711.DecodeMuLaw(g711.EncodeMuLaw(pcm[i])) {
+			t.Fatalf("μ-law sample %d differs", i)
+		}
+	}
+}
+
+func abs(v int16) int32 {
+	if v < 0 {
+		return -int32(v)
+	}
+	return int32(v)
+}
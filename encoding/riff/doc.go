@@ -0,0 +1,5 @@
+// Package riff implements reading and writing of the chunks of RIFF
+// (Resource Interchange File Format) files, like WAVE and AVI.
+// The specification could be found here:
+//   https://www.aelius.com/njh/wavemetatools/doc/riffmci.pdf
+package riff
@@ -0,0 +1,244 @@
+package riff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+type (
+	// ID is the four character code identifying a chunk.
+	ID [4]byte
+
+	// Chunk is a chunk being read. It's a bounded reader of the chunk
+	// data, that returns io.EOF at the end of the chunk (not
+	// including the pad byte).
+	Chunk struct {
+		ID     ID
+		Size   uint32 // size of the data, without the pad byte
+		Offset int64  // offset of the chunk header in the stream
+
+		r         io.Reader
+		remaining int64
+	}
+
+	// Reader reads the sequence of chunks of a RIFF stream (or of
+	// the data of a RIFF or LIST chunk, see Chunk.List).
+	Reader struct {
+		r      io.Reader
+		offset int64  // offset of the next chunk header
+		chunk  *Chunk // last chunk returned by Next
+	}
+
+	// Writer writes chunks into a RIFF stream, padding the chunks of
+	// odd size.
+	Writer struct {
+		w io.Writer
+	}
+)
+
+// HeaderSize is the size of the chunk header: ID and size.
+const HeaderSize = 8
+
+// Well known chunk IDs.
+var (
+	RIFF = ID{'R', 'I', 'F', 'F'}
+	LIST = ID{'L', 'I', 'S', 'T'}
+)
+
+// NewID creates the chunk ID of the four character code s, padded
+// with spaces if shorter.
+func NewID(s string) ID {
+	id := ID{' ', ' ', ' ', ' '}
+	copy(id[:], s)
+	return id
+}
+
+// String returns the four character code.
+func (id ID) String() string {
+	return string(id[:])
+}
+
+// Valid tells if the ID has only printable ASCII characters.
+func (id ID) Valid() bool {
+	for _, c := range id {
+		if c < 0x20 || c > 0x7E {
+			return false
+		}
+	}
+	return true
+}
+
+// PaddedSize returns the size of a chunk with datasz bytes of data,
+// including the header and the pad byte.
+func PaddedSize(datasz uint32) int64 {
+	return HeaderSize + int64(datasz) + int64(datasz&1)
+}
+
+// NewReader creates a reader of the chunks of r. The offsets of the
+// chunks are relative to the current position of r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: r}
+}
+
+// Next skips what is left of the previous chunk (and its pad byte)
+// and reads the header of the next chunk. It returns io.EOF when
+// there are no more chunks.
+func (r *Reader) Next() (*Chunk, error) {
+	err := r.skip()
+	if err != nil {
+		return nil, err
+	}
+
+	var hdr [HeaderSize]byte
+	n, err := io.ReadFull(r.r, hdr[:])
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading chunk header at offset %d: %d bytes read: %s",
+			r.offset, n, err)
+	}
+
+	c := &Chunk{
+		Offset: r.offset,
+		Size:   binary.LittleEndian.Uint32(hdr[4:]),
+		r:      r.r,
+	}
+	copy(c.ID[:], hdr[:4])
+	c.remaining = int64(c.Size)
+
+	r.chunk = c
+	r.offset += HeaderSize
+	return c, nil
+}
+
+// skip discards the unread data and the pad byte of the last chunk.
+// A missing pad byte at the end of the stream is tolerated.
+func (r *Reader) skip() error {
+	c := r.chunk
+	if c == nil {
+		return nil
+	}
+	r.chunk = nil
+
+	if c.remaining > 0 {
+		_, err := io.CopyN(ioutil.Discard, c, c.remaining)
+		if err != nil {
+			return fmt.Errorf("skipping chunk %q: %s", c.ID, err)
+		}
+	}
+	r.offset += int64(c.Size)
+
+	if c.Size&1 == 1 {
+		var pad [1]byte
+		n, err := r.r.Read(pad[:])
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("skipping pad byte of chunk %q: %s", c.ID, err)
+		}
+		r.offset += int64(n)
+	}
+	return nil
+}
+
+// Read reads the data of the chunk. It returns io.ErrUnexpectedEOF if
+// the stream ends before the declared size.
+func (c *Chunk) Read(p []byte) (int, error) {
+	if c.remaining <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > c.remaining {
+		p = p[:c.remaining]
+	}
+
+	n, err := c.r.Read(p)
+	c.remaining -= int64(n)
+	if err == io.EOF && c.remaining > 0 {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// Bytes reads all the unread data of the chunk. The buffer grows with
+// the data read, not with the declared size, that could be bogus.
+func (c *Chunk) Bytes() ([]byte, error) {
+	data, err := ioutil.ReadAll(c)
+	if err != nil {
+		return data, fmt.Errorf("reading chunk %q: %d bytes read of %d: %s",
+			c.ID, len(data), c.Size, err)
+	}
+	return data, nil
+}
+
+// List reads the form type of a RIFF chunk (or the list type of a
+// LIST chunk), returning a reader of its subchunks, with offsets
+// relative to the same stream of c.
+func (c *Chunk) List() (ID, *Reader, error) {
+	var listType ID
+	_, err := io.ReadFull(c, listType[:])
+	if err != nil {
+		return listType, nil, fmt.Errorf("reading list type of chunk %q: %s", c.ID, err)
+	}
+
+	return listType, &Reader{
+		r:      c,
+		offset: c.Offset + HeaderSize + 4,
+	}, nil
+}
+
+// NewWriter creates a writer of chunks into w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// WriteChunk writes the chunk with data, followed by the pad byte if
+// the size is odd.
+func (w *Writer) WriteChunk(id ID, data []byte) error {
+	err := w.WriteHeader(id, uint32(len(data)))
+	if err != nil {
+		return err
+	}
+
+	_, err = w.w.Write(data)
+	if err != nil {
+		return err
+	}
+	return w.WritePad(uint32(len(data)))
+}
+
+// WriteList writes a LIST chunk of type listType with the chunks
+// encoded by write.
+func (w *Writer) WriteList(listType ID, write func(w *Writer) error) error {
+	var buf bytes.Buffer
+	buf.Write(listType[:])
+
+	err := write(NewWriter(&buf))
+	if err != nil {
+		return err
+	}
+	return w.WriteChunk(LIST, buf.Bytes())
+}
+
+// WriteHeader writes just the header of a chunk with size bytes, for
+// chunks whose data is written directly into the underlying writer
+// (the pad byte must be written with WritePad).
+func (w *Writer) WriteHeader(id ID, size uint32) error {
+	var hdr [HeaderSize]byte
+	copy(hdr[:4], id[:])
+	binary.LittleEndian.PutUint32(hdr[4:], size)
+
+	_, err := w.w.Write(hdr[:])
+	return err
+}
+
+// WritePad writes the pad byte of a chunk with size bytes, if the size
+// is odd.
+func (w *Writer) WritePad(size uint32) error {
+	if size&1 == 0 {
+		return nil
+	}
+	_, err := w.w.Write([]byte{0})
+	return err
+}
@@ -0,0 +1,176 @@
+package riff_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/NeowayLabs/signal/encoding/riff"
+)
+
+func assertNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadChunks(t *testing.T) {
+	var buf bytes.Buffer
+	w := riff.NewWriter(&buf)
+	assertNoError(t, w.WriteChunk(riff.NewID("odd"), []byte{1, 2, 3}))
+	assertNoError(t, w.WriteChunk(riff.NewID("even"), []byte{4, 5}))
+	assertNoError(t, w.WriteChunk(riff.NewID("skip"), []byte{6, 7, 8, 9, 10}))
+	assertNoError(t, w.WriteChunk(riff.NewID("last"), []byte{11}))
+
+	// pad bytes after the odd chunks
+	if buf.Len() != 4*8+4+2+6+2 {
+		t.Fatalf("unexpected size: %d", buf.Len())
+	}
+
+	r := riff.NewReader(bytes.NewReader(buf.Bytes()))
+	for _, expected := range []struct {
+		id     string
+		offset int64
+		data   []byte
+	}{
+		{"odd ", 0, []byte{1, 2, 3}},
+		{"even", 12, []byte{4, 5}},
+		{"skip", 22, nil}, // not read
+		{"last", 36, []byte{11}},
+	} {
+		c, err := r.Next()
+		assertNoError(t, err)
+
+		if c.ID.String() != expected.id || c.Offset != expected.offset {
+			t.Fatalf("unexpected chunk: %q at %d", c.ID, c.Offset)
+		}
+		if expected.data == nil {
+			continue
+		}
+
+		data, err := ioutil.ReadAll(c)
+		assertNoError(t, err)
+		if !bytes.Equal(data, expected.data) || c.Size != uint32(len(data)) {
+			t.Fatalf("chunk %q: unexpected data: %v", c.ID, data)
+		}
+	}
+
+	_, err := r.Next()
+	if err != io.EOF {
+		t.Fatalf("expected EOF, got: %v", err)
+	}
+}
+
+func TestReadList(t *testing.T) {
+	var buf bytes.Buffer
+	w := riff.NewWriter(&buf)
+	err := w.WriteList(riff.NewID("INFO"), func(w *riff.Writer) error {
+		err := w.WriteChunk(riff.NewID("INAM"), []byte("name\x00"))
+		if err != nil {
+			return err
+		}
+		return w.WriteChunk(riff.NewID("ICMT"), []byte("comment\x00"))
+	})
+	assertNoError(t, err)
+	assertNoError(t, w.WriteChunk(riff.NewID("data"), []byte{1, 2}))
+
+	r := riff.NewReader(&buf)
+	list, err := r.Next()
+	assertNoError(t, err)
+	if list.ID != riff.LIST {
+		t.Fatalf("unexpected chunk: %q", list.ID)
+	}
+
+	listType, sub, err := list.List()
+	assertNoError(t, err)
+	if listType.String() != "INFO" {
+		t.Fatalf("unexpected list type: %q", listType)
+	}
+
+	var names []string
+	for {
+		c, err := sub.Next()
+		if err == io.EOF {
+			break
+		}
+		assertNoError(t, err)
+
+		data, err := c.Bytes()
+		assertNoError(t, err)
+		names = append(names, c.ID.String()+"="+string(data))
+
+		if c.ID.String() == "ICMT" && c.Offset != 8+4+8+6 {
+			t.Fatalf("unexpected offset: %d", c.Offset)
+		}
+	}
+	if len(names) != 2 || names[0] != "INAM=name\x00" || names[1] != "ICMT=comment\x00" {
+		t.Fatalf("unexpected subchunks: %q", names)
+	}
+
+	// the reader continues after the list
+	c, err := r.Next()
+	assertNoError(t, err)
+	if c.ID.String() != "data" || c.Offset != list.Offset+riff.PaddedSize(list.Size) {
+		t.Fatalf("unexpected chunk: %q at %d", c.ID, c.Offset)
+	}
+}
+
+func TestReadTruncated(t *testing.T) {
+	var buf bytes.Buffer
+	w := riff.NewWriter(&buf)
+	assertNoError(t, w.WriteChunk(riff.NewID("data"), []byte{1, 2, 3, 4}))
+
+	r := riff.NewReader(bytes.NewReader(buf.Bytes()[:10]))
+	c, err := r.Next()
+	assertNoError(t, err)
+
+	_, err = c.Bytes()
+	if err == nil {
+		t.Fatal("truncated chunk must fail")
+	}
+
+	// bogus size, the data read is returned without allocating it
+	var bogus bytes.Buffer
+	assertNoError(t, riff.NewWriter(&bogus).WriteHeader(riff.NewID("junk"), 0x7FFFFFF0))
+	bogus.Write([]byte{1, 2})
+
+	c, err = riff.NewReader(&bogus).Next()
+	assertNoError(t, err)
+	data, err := c.Bytes()
+	if err == nil || !bytes.Equal(data, []byte{1, 2}) {
+		t.Fatalf("unexpected data: %v (%v)", data, err)
+	}
+
+	// truncated header
+	r = riff.NewReader(bytes.NewReader(buf.Bytes()[:5]))
+	_, err = r.Next()
+	if err == nil || err == io.EOF {
+		t.Fatalf("truncated header must fail: %v", err)
+	}
+}
+
+func TestMissingPadAtEnd(t *testing.T) {
+	var buf bytes.Buffer
+	w := riff.NewWriter(&buf)
+	assertNoError(t, w.WriteChunk(riff.NewID("odd"), []byte{1}))
+
+	r := riff.NewReader(bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+	_, err := r.Next()
+	assertNoError(t, err)
+
+	_, err = r.Next()
+	if err != io.EOF {
+		t.Fatalf("expected EOF, got: %v", err)
+	}
+}
+
+func TestID(t *testing.T) {
+	if riff.NewID("fmt") != riff.NewID("fmt ") {
+		t.Fatal("short IDs must be padded with spaces")
+	}
+	if !riff.NewID("data").Valid() || (riff.ID{0, 'a', 'b', 'c'}).Valid() {
+		t.Fatal("unexpected validity")
+	}
+}
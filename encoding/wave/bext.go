@@ -0,0 +1,169 @@
+package wave
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/NeowayLabs/signal/encoding/riff"
+)
+
+type (
+	// Bext is the metadata of the broadcast audio extension chunk of
+	// the Broadcast Wave Format (EBU Tech 3285, version 2).
+	// The text fields are ASCII, limited to the sizes of the fields.
+	Bext struct {
+		Description         string // up to 256 characters
+		Originator          string // up to 32 characters
+		OriginatorReference string // up to 32 characters
+		OriginationDate     string // yyyy-mm-dd
+		OriginationTime     string // hh:mm:ss
+
+		// TimeReference is the time of the first sample, as the
+		// number of samples since midnight.
+		TimeReference uint64
+
+		// Version of the chunk, the loudness fields are defined from
+		// version 2.
+		Version uint16
+		UMID    [64]byte // SMPTE UMID
+
+		// Loudness fields (EBU R 128), in hundredths of LUFS (or LU
+		// and dBTP), eg. -2300 is -23 LUFS.
+		LoudnessValue        int16
+		LoudnessRange        int16
+		MaxTruePeakLevel     int16
+		MaxMomentaryLoudness int16
+		MaxShortTermLoudness int16
+
+		// CodingHistory describes the coding processes applied to the
+		// audio, one process per line ended by CR/LF.
+		CodingHistory string
+	}
+
+	// bextFields is the layout of the fixed size part of the bext
+	// chunk.
+	bextFields struct {
+		Description          [256]byte
+		Originator           [32]byte
+		OriginatorReference  [32]byte
+		OriginationDate      [10]byte
+		OriginationTime      [8]byte
+		TimeReference        uint64
+		Version              uint16
+		UMID                 [64]byte
+		LoudnessValue        int16
+		LoudnessRange        int16
+		MaxTruePeakLevel     int16
+		MaxMomentaryLoudness int16
+		MaxShortTermLoudness int16
+		Reserved             [180]byte
+	}
+)
+
+// bextSize is the size of the fixed part of the bext chunk.
+const bextSize = 602
+
+var bextID = riff.NewID("bext")
+
+// Chunk returns the bext chunk, that could be given to
+// NewStreamEncoder. It fails if a text field doesn't fit in its size.
+func (b Bext) Chunk() (Chunk, error) {
+	var f bextFields
+	for _, field := range []struct {
+		name  string
+		dst   []byte
+		value string
+	}{
+		{"description", f.Description[:], b.Description},
+		{"originator", f.Originator[:], b.Originator},
+		{"originator reference", f.OriginatorReference[:], b.OriginatorReference},
+		{"origination date", f.OriginationDate[:], b.OriginationDate},
+		{"origination time", f.OriginationTime[:], b.OriginationTime},
+	} {
+		if len(field.value) > len(field.dst) {
+			return Chunk{}, fmt.Errorf("encoding bext: %s longer than %d bytes: %q",
+				field.name, len(field.dst), field.value)
+		}
+		copy(field.dst, field.value)
+	}
+
+	f.TimeReference = b.TimeReference
+	f.Version = b.Version
+	f.UMID = b.UMID
+	f.LoudnessValue = b.LoudnessValue
+	f.LoudnessRange = b.LoudnessRange
+	f.MaxTruePeakLevel = b.MaxTruePeakLevel
+	f.MaxMomentaryLoudness = b.MaxMomentaryLoudness
+	f.MaxShortTermLoudness = b.MaxShortTermLoudness
+
+	buf := bytes.NewBuffer(make([]byte, 0, bextSize+len(b.CodingHistory)))
+	err := binary.Write(buf, binary.LittleEndian, &f)
+	if err != nil {
+		return Chunk{}, fmt.Errorf("encoding bext: %s", err)
+	}
+	buf.WriteString(b.CodingHistory)
+
+	return Chunk{ID: bextID, Data: buf.Bytes()}, nil
+}
+
+// parseBext parses the bext chunk c.
+func parseBext(c Chunk) (Bext, error) {
+	if len(c.Data) < bextSize {
+		return Bext{}, fmt.Errorf("parsing bext: invalid size: %d", len(c.Data))
+	}
+
+	var f bextFields
+	err := binary.Read(bytes.NewReader(c.Data), binary.LittleEndian, &f)
+	if err != nil {
+		return Bext{}, fmt.Errorf("parsing bext: %s", err)
+	}
+
+	return Bext{
+		Description:          cstring(f.Description[:]),
+		Originator:           cstring(f.Originator[:]),
+		OriginatorReference:  cstring(f.OriginatorReference[:]),
+		OriginationDate:      cstring(f.OriginationDate[:]),
+		OriginationTime:      cstring(f.OriginationTime[:]),
+		TimeReference:        f.TimeReference,
+		Version:              f.Version,
+		UMID:                 f.UMID,
+		LoudnessValue:        f.LoudnessValue,
+		LoudnessRange:        f.LoudnessRange,
+		MaxTruePeakLevel:     f.MaxTruePeakLevel,
+		MaxMomentaryLoudness: f.MaxMomentaryLoudness,
+		MaxShortTermLoudness: f.MaxShortTermLoudness,
+		CodingHistory:        cstring(c.Data[bextSize:]),
+	}, nil
+}
+
+// cstring returns the text of a field padded with NUL bytes.
+func cstring(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
+
+// Bext returns the metadata of the first bext chunk (see Chunks), or
+// nil if there's none.
+func (d *Decoder) Bext() (*Bext, error) {
+	c, ok := d.chunk(isChunk(bextID))
+	if !ok {
+		return nil, nil
+	}
+	b, err := parseBext(c)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// SetBext sets the metadata written as a bext chunk, replacing the
+// bext chunks given to SetChunks. A nil bext removes them.
+func (e *Encoder) SetBext(b *Bext) {
+	e.bext = &metadata{match: isChunk(bextID)}
+	if b != nil {
+		e.bext.chunk = (*b).Chunk
+	}
+}
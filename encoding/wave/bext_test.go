@@ -0,0 +1,111 @@
+package wave_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/NeowayLabs/signal/encoding/riff"
+	"github.com/NeowayLabs/signal/encoding/wave"
+)
+
+func decodeChunks(t *testing.T, audio []byte) *wave.Decoder {
+	t.Helper()
+	d := wave.NewDecoder(bytes.NewReader(audio))
+	d.Strict()
+	var samples []int16
+	_, err := d.DecodeInt16(&samples)
+	assertNoError(t, err)
+	return d
+}
+
+func TestBextRoundTrip(t *testing.T) {
+	expected := wave.Bext{
+		Description:          "call 4242",
+		Originator:           "NeowayLabs",
+		OriginatorReference:  "USID0001",
+		OriginationDate:      "2017-03-21",
+		OriginationTime:      "13:45:07",
+		TimeReference:        0x100000002,
+		Version:              2,
+		LoudnessValue:        -2300,
+		LoudnessRange:        540,
+		MaxTruePeakLevel:     -100,
+		MaxMomentaryLoudness: -1800,
+		MaxShortTermLoudness: -2000,
+		CodingHistory:        "A=PCM,F=8000,W=16,M=mono,T=signal\r\n",
+	}
+	expected.UMID[0] = 0x06
+
+	enc := wave.NewEncoder(wave.NewPCM(1, 8000, 16))
+	enc.SetBext(&expected)
+	audio, err := enc.EncodeInt16([]int16{1, 2})
+	assertNoError(t, err)
+
+	// header, bext chunk (padded) and samples
+	if len(audio) != 44+8+602+len(expected.CodingHistory)+1+4 {
+		t.Fatalf("unexpected size: %d", len(audio))
+	}
+
+	d := decodeChunks(t, audio)
+	got, err := d.Bext()
+	assertNoError(t, err)
+	if got == nil || !reflect.DeepEqual(*got, expected) {
+		t.Fatalf("unexpected bext: %#v", got)
+	}
+
+	// replaces the decoded chunk
+	expected.Description = "updated"
+	enc.SetChunks(d.Chunks())
+	enc.SetBext(&expected)
+	audio, err = enc.EncodeInt16([]int16{1, 2})
+	assertNoError(t, err)
+
+	d = decodeChunks(t, audio)
+	got, err = d.Bext()
+	assertNoError(t, err)
+	if len(d.Chunks()) != 1 || got.Description != "updated" {
+		t.Fatalf("unexpected bext: %#v", got)
+	}
+
+	// removes the decoded chunk
+	enc.SetChunks(d.Chunks())
+	enc.SetBext(nil)
+	audio, err = enc.EncodeInt16([]int16{1, 2})
+	assertNoError(t, err)
+
+	d = decodeChunks(t, audio)
+	got, err = d.Bext()
+	assertNoError(t, err)
+	if got != nil || len(d.Chunks()) != 0 {
+		t.Fatalf("bext not removed: %#v", got)
+	}
+}
+
+func TestBextAbsent(t *testing.T) {
+	audio, err := wave.NewEncoder(wave.NewPCM(1, 8000, 16)).EncodeInt16([]int16{1})
+	assertNoError(t, err)
+
+	b, err := decodeChunks(t, audio).Bext()
+	assertNoError(t, err)
+	if b != nil {
+		t.Fatalf("unexpected bext: %#v", b)
+	}
+}
+
+func TestBextFieldTooLong(t *testing.T) {
+	enc := wave.NewEncoder(wave.NewPCM(1, 8000, 16))
+	enc.SetBext(&wave.Bext{OriginationDate: "2017-03-21T13:45"})
+	_, err := enc.EncodeInt16([]int16{1})
+	assertError(t, err)
+}
+
+func TestBextInvalidSize(t *testing.T) {
+	enc := wave.NewEncoder(wave.NewPCM(1, 8000, 16))
+	enc.SetChunks([]wave.Chunk{{ID: riff.NewID("bext"), Data: make([]byte, 100)}})
+	audio, err := enc.EncodeInt16([]int16{1})
+	assertNoError(t, err)
+
+	_, err = decodeChunks(t, audio).Bext()
+	assertError(t, err)
+}
@@ -1,10 +1,13 @@
 package wave
 
 import (
+	"bytes"
 	"encoding/binary"
 	"fmt"
 	"io"
-	"unsafe"
+	"io/ioutil"
+
+	"github.com/NeowayLabs/signal/encoding/riff"
 )
 
 type (
@@ -12,9 +15,33 @@ type (
 	Decoder struct {
 		input     io.Reader        // input stream
 		byteOrder binary.ByteOrder // decoder's byte order for data samples
+
+		hdr        Header // header of the stream being decoded
+		hdrDecoded bool
+		remaining  uint32 // bytes of the data chunk not read yet
+		unbounded  bool   // data chunk size unknown, read until EOF
+		consumed   uint32 // bytes of the data chunk read
+		buf        []byte // raw samples buffer
+
+		strict     bool        // rejects headers with violations
+		violations []Violation // violations of the header (lenient mode)
+
+		chunks      []Chunk // chunks other than fmt and data
+		trailerRead bool    // chunks after the data chunk were read
+	}
+
+	// Chunk is a chunk of the WAVE file other than fmt and data, like
+	// LIST, fact or cue, kept to be written back when re-encoding.
+	Chunk struct {
+		ID   riff.ID
+		Data []byte
 	}
 )
 
+// decodeBlockSize is the number of samples read at once by the
+// Decode* functions.
+const decodeBlockSize = 4096
+
 // NewDecoder creates a new WAVE decoder using Little-Endian as default
 // byte order of data samples (use d.BigEndian() to opt for big-endian).
 func NewDecoder(r io.Reader) *Decoder {
@@ -34,71 +61,111 @@ func (d *Decoder) BigEndian() {
 	d.byteOrder = binary.BigEndian
 }
 
-// DecodeInt16 decodes the WAV buffer, returning the wave header and
-// filling data with the audio samples.
-// In case the data chunk is corrupted or there's some other error
-// parsing the samples, the parsed header is returned to inspection also
-// (useful to check corrupted WAV files).
-func (d *Decoder) DecodeInt16(data *[]int16) (hdr Header, err error) {
+// Strict configures the decoder to reject the headers with any
+// violation (see Header.Validate) and the malformed chunks after the
+// data chunk.
+func (d *Decoder) Strict() {
+	d.strict = true
+}
+
+// Lenient configures the decoder to accept the headers with
+// violations, that are reported by Violations. This is the default.
+func (d *Decoder) Lenient() {
+	d.strict = false
+}
+
+// Violations returns the violations found in the decoded header, of
+// any severity.
+func (d *Decoder) Violations() []Violation {
+	return d.violations
+}
+
+// decode decodes the WAV buffer, calling read until the end of the
+// data chunk. The read function must decode a block of samples.
+func (d *Decoder) decode(name string, read func() (int, error)) (hdr Header, err error) {
 	hdr, err = d.DecodeHeader()
 	if err != nil {
 		return Header{}, err
 	}
 
-	var bytesRead uint32
-	for bytesRead < hdr.DataBlockSize {
-		var sample int16
-		err := binary.Read(d.input, d.byteOrder, &sample)
+	for {
+		_, err := read()
+		if err == io.EOF {
+			return hdr, nil
+		}
 		if err != nil {
-			return hdr, fmt.Errorf("decoding int16: bytes read[%d], total[%d]: %s",
-				bytesRead,
+			return hdr, fmt.Errorf("decoding %s: bytes read[%d], total[%d]: %s",
+				name,
+				d.consumed,
 				hdr.DataBlockSize,
 				err)
 		}
-		*data = append(*data, sample)
-		bytesRead += uint32(unsafe.Sizeof(sample))
 	}
+}
 
-	return hdr, nil
+// DecodeUint8 decodes the WAV buffer of 8 bit unsigned samples,
+// returning the wave header and filling data with the audio samples.
+// It has the same semantics of DecodeInt16.
+func (d *Decoder) DecodeUint8(data *[]uint8) (hdr Header, err error) {
+	buf := make([]uint8, decodeBlockSize)
+	return d.decode("uint8", func() (int, error) {
+		n, err := d.ReadUint8(buf)
+		*data = append(*data, buf[:n]...)
+		return n, err
+	})
 }
 
-// DecodeFloat32 decodes the WAV buffer, returning the wave header and
+// DecodeInt16 decodes the WAV buffer, returning the wave header and
 // filling data with the audio samples.
 // In case the data chunk is corrupted or there's some other error
 // parsing the samples, the parsed header is returned to inspection also
 // (useful to check corrupted WAV files).
-func (d *Decoder) DecodeFloat32(data *[]float32) (hdr Header, err error) {
-	hdr, err = d.DecodeHeader()
-	if err != nil {
-		return Header{}, err
-	}
-
-	const maxval float32 = 1.0
-	const minval float32 = -1.0
-
-	var bytesRead uint32
-	for bytesRead < hdr.DataBlockSize {
-		var sample float32
-		err := binary.Read(d.input, d.byteOrder, &sample)
-		if err != nil {
-			return hdr, fmt.Errorf("decoding float32: bytes read[%d], total[%d]: %s",
-				bytesRead,
-				hdr.DataBlockSize,
-				err)
-		}
+func (d *Decoder) DecodeInt16(data *[]int16) (hdr Header, err error) {
+	buf := make([]int16, decodeBlockSize)
+	return d.decode("int16", func() (int, error) {
+		n, err := d.ReadInt16(buf)
+		*data = append(*data, buf[:n]...)
+		return n, err
+	})
+}
 
-		if sample < minval || sample > maxval {
-			return hdr, fmt.Errorf(
-				"sample[%f] is outside the valid value range for a PCM float",
-				sample,
-			)
-		}
+// DecodeInt24 decodes the WAV buffer of packed 24 bit samples,
+// returning the wave header and filling data with the audio samples
+// sign extended to int32.
+// It has the same semantics of DecodeInt16.
+func (d *Decoder) DecodeInt24(data *[]int32) (hdr Header, err error) {
+	buf := make([]int32, decodeBlockSize)
+	return d.decode("int24", func() (int, error) {
+		n, err := d.ReadInt24(buf)
+		*data = append(*data, buf[:n]...)
+		return n, err
+	})
+}
 
-		*data = append(*data, sample)
-		bytesRead += uint32(unsafe.Sizeof(sample))
-	}
+// DecodeInt32 decodes the WAV buffer of 32 bit samples, returning the
+// wave header and filling data with the audio samples.
+// It has the same semantics of DecodeInt16.
+func (d *Decoder) DecodeInt32(data *[]int32) (hdr Header, err error) {
+	buf := make([]int32, decodeBlockSize)
+	return d.decode("int32", func() (int, error) {
+		n, err := d.ReadInt32(buf)
+		*data = append(*data, buf[:n]...)
+		return n, err
+	})
+}
 
-	return hdr, nil
+// DecodeFloat32 decodes the WAV buffer, returning the wave header and
+// filling data with the audio samples.
+// In case the data chunk is corrupted or there's some other error
+// parsing the samples, the parsed header is returned to inspection also
+// (useful to check corrupted WAV files).
+func (d *Decoder) DecodeFloat32(data *[]float32) (hdr Header, err error) {
+	buf := make([]float32, decodeBlockSize)
+	return d.decode("float32", func() (int, error) {
+		n, err := d.ReadFloat32(buf)
+		*data = append(*data, buf[:n]...)
+		return n, err
+	})
 }
 
 func (d *Decoder) parseRIFFHdr() (RiffHeader, error) {
@@ -114,74 +181,211 @@ func (d *Decoder) parseRIFFHdr() (RiffHeader, error) {
 }
 
 // DecodeHeader decodes just the header of the WAV.
+// The chunks other than fmt and data found before the data chunk are
+// kept (see Chunks).
 func (d *Decoder) DecodeHeader() (Header, error) {
 	riffhdr, err := d.parseRIFFHdr()
 	if err != nil {
 		return Header{}, err
 	}
 
-	// FMT chunk
-	var chunk [4]byte
-	var chunkFmt RiffChunkFmt
+	d.chunks = nil
+	d.trailerRead = false
+	chunks := riff.NewReader(d.input)
 
-	err = binary.Read(d.input, binary.LittleEndian, &chunk)
+	// FMT chunk
+	c, err := chunks.Next()
 	if err != nil {
 		return Header{}, fmt.Errorf("parsing fmt chunk: %s", err)
 	}
 
-	if ckName := string(chunk[:]); ckName != "fmt " {
+	if ckName := c.ID.String(); ckName != "fmt " {
 		return Header{}, fmt.Errorf("Unexpected chunk type: %s", ckName)
 	}
 
-	err = binary.Read(d.input, binary.LittleEndian, &chunkFmt)
+	chunkFmt, ext, err := parseChunkFmt(c)
 	if err != nil {
-		return Header{}, fmt.Errorf("parsing fmt chunk: %s", err)
+		return Header{}, err
 	}
 
-	if !isValidWavFormat(chunkFmt.AudioFormat) {
-		return Header{}, fmt.Errorf("Isn't an audio format: format[%d]", chunkFmt.AudioFormat)
+	var otherChunks uint64
+	for c.ID.String() != "data" {
+		c, err = chunks.Next()
+		if err != nil {
+			return Header{}, fmt.Errorf("Expected data chunk: %s", err)
+		}
+
+		if c.ID.String() != "data" {
+			data, err := c.Bytes()
+			if err != nil {
+				return Header{}, fmt.Errorf("parsing chunks: %s", err)
+			}
+			d.chunks = append(d.chunks, Chunk{ID: c.ID, Data: data})
+			otherChunks += uint64(riff.PaddedSize(c.Size))
+		}
+	}
+
+	hdr := Header{
+		RiffHeader:    riffhdr,
+		RiffChunkFmt:  chunkFmt,
+		Extension:     ext,
+		DataBlockSize: c.Size,
+	}
+
+	d.violations = hdr.validate(otherChunks)
+	if d.strict && len(d.violations) > 0 {
+		return hdr, validationError(d.violations)
+	}
+
+	d.hdr = hdr
+	d.hdrDecoded = true
+	d.remaining = c.Size
+	d.consumed = 0
+	d.unbounded = c.Size == streamingSize
+	return d.hdr, nil
+}
+
+// parseChunkFmt parses the fmt chunk and, for extensible formats, its
+// extension. The extra params of other formats are skipped.
+func parseChunkFmt(c *riff.Chunk) (RiffChunkFmt, FmtExtension, error) {
+	var chunkFmt RiffChunkFmt
+	var ext FmtExtension
+
+	if c.Size < 16 {
+		return chunkFmt, ext, fmt.Errorf("Invalid fmt chunk size: %d", c.Size)
+	}
+
+	// the fmt struct starts with the chunk size
+	raw := make([]byte, 4+16)
+	binary.LittleEndian.PutUint32(raw, c.Size)
+	_, err := io.ReadFull(c, raw[4:])
+	if err != nil {
+		return chunkFmt, ext, fmt.Errorf("parsing fmt chunk: %s", err)
+	}
+	err = binary.Read(bytes.NewReader(raw), binary.LittleEndian, &chunkFmt)
+	if err != nil {
+		return chunkFmt, ext, fmt.Errorf("parsing fmt chunk: %s", err)
+	}
+
+	if chunkFmt.AudioFormat != FormatExtensible &&
+		!isValidWavFormat(chunkFmt.AudioFormat) {
+		return chunkFmt, ext, fmt.Errorf("Isn't an audio format: format[%d]", chunkFmt.AudioFormat)
+	}
+
+	if chunkFmt.LengthOfHeader == 16 {
+		return chunkFmt, ext, nil
+	}
+	if chunkFmt.LengthOfHeader < 18 {
+		return chunkFmt, ext, fmt.Errorf("Invalid fmt chunk size: %d",
+			chunkFmt.LengthOfHeader)
 	}
 
-	if chunkFmt.LengthOfHeader != 16 {
-		var extraparams uint16
-		// Get extra params size
-		if err = binary.Read(d.input, binary.LittleEndian, &extraparams); err != nil {
-			return Header{}, fmt.Errorf("error getting extra fmt params: %s", err)
+	var extraparams uint16
+	// Get extra params size
+	if err = binary.Read(c, binary.LittleEndian, &extraparams); err != nil {
+		return chunkFmt, ext, fmt.Errorf("error getting extra fmt params: %s", err)
+	}
+
+	if chunkFmt.AudioFormat == FormatExtensible {
+		if extraparams < fmtExtensionSize || chunkFmt.LengthOfHeader < 18+fmtExtensionSize {
+			return chunkFmt, ext, fmt.Errorf("Invalid extensible fmt: cbSize[%d]",
+				extraparams)
 		}
-		// TODO: Skip for now
-		_, err = d.input.Read(make([]byte, int(extraparams)))
+
+		err = binary.Read(c, binary.LittleEndian, &ext)
 		if err != nil {
-			return Header{}, fmt.Errorf("error skipping extra params: %s", err)
+			return chunkFmt, ext, fmt.Errorf("parsing fmt extension: %s", err)
 		}
+
+		if _, ok := ext.SubFormat.Format(); !ok {
+			return chunkFmt, ext, fmt.Errorf("Isn't an audio format: subformat[%s]",
+				ext.SubFormat)
+		}
+	}
+
+	// skips the remaining extra params
+	_, err = io.Copy(ioutil.Discard, c)
+	if err != nil {
+		return chunkFmt, ext, fmt.Errorf("error skipping extra params: %s", err)
+	}
+	return chunkFmt, ext, nil
+}
+
+// Chunks returns the chunks other than fmt and data read so far: the
+// chunks before the data chunk after the header is decoded, and the
+// chunks after the data chunk once all the samples are read.
+// They could be written back with Encoder.SetChunks.
+func (d *Decoder) Chunks() []Chunk {
+	return d.chunks
+}
+
+// chunk returns the first chunk matching (see Chunks).
+func (d *Decoder) chunk(match func(Chunk) bool) (Chunk, bool) {
+	for _, c := range d.Chunks() {
+		if match(c) {
+			return c, true
+		}
+	}
+	return Chunk{}, false
+}
+
+// endOfData reads the chunks after the data chunk, once its end is
+// reached. Malformed chunks are an error in strict mode and are
+// reported by Violations otherwise.
+func (d *Decoder) endOfData() error {
+	if d.trailerRead {
+		return nil
 	}
+	d.trailerRead = true
 
-	var chunkSize uint32
-	for string(chunk[:]) != "data" {
-		// Read chunkID
-		err = binary.Read(d.input, binary.BigEndian, &chunk)
+	err := d.readTrailer()
+	if err == nil {
+		return nil
+	}
+	if d.strict {
+		return err
+	}
+	d.violations = append(d.violations, Violation{
+		Field:    "Chunks",
+		Expected: "well-formed chunks after data",
+		Found:    err.Error(),
+		Severity: SeverityWarning,
+	})
+	return nil
+}
+
+// readTrailer reads the chunks after the data chunk. The data of the
+// chunks is read as it arrives, so chunks declaring sizes bigger than
+// the input don't allocate their whole size.
+func (d *Decoder) readTrailer() error {
+	if d.hdr.DataBlockSize&1 == 1 {
+		// pad byte of the data chunk, that may be missing
+		var pad [1]byte
+		_, err := d.input.Read(pad[:])
+		if err == io.EOF {
+			return nil
+		}
 		if err != nil {
-			return Header{}, fmt.Errorf("Expected data chunkid: %s", err)
+			return err
 		}
+	}
 
-		err = binary.Read(d.input, binary.LittleEndian, &chunkSize)
+	chunks := riff.NewReader(d.input)
+	for {
+		c, err := chunks.Next()
+		if err == io.EOF {
+			return nil
+		}
 		if err != nil {
-			return Header{}, fmt.Errorf("Expected data chunkSize: %s", err)
+			return fmt.Errorf("parsing chunks after data: %s", err)
 		}
 
-		// ignores LIST chunkIDs (unused for now)
-		if string(chunk[:]) != "data" {
-			_, err = d.input.Read(make([]byte, int(chunkSize)))
-			if err != nil {
-				return Header{}, fmt.Errorf("ignoring LIST chunks: %s", err)
-			}
+		data, err := c.Bytes()
+		if err != nil {
+			return fmt.Errorf("parsing chunks after data: %s", err)
 		}
+		d.chunks = append(d.chunks, Chunk{ID: c.ID, Data: data})
 	}
-
-	return Header{
-		RiffHeader:    riffhdr,
-		RiffChunkFmt:  chunkFmt,
-		DataBlockSize: uint32(chunkSize),
-	}, nil
 }
 
 func isValidWavFormat(fmt uint16) bool {
@@ -12,6 +12,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/NeowayLabs/signal/encoding/riff"
 	"github.com/NeowayLabs/signal/encoding/wave"
 )
 
@@ -219,3 +220,143 @@ func TestFloatSamplesMustBeNormalized(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeExtensibleUnknownSubFormat(t *testing.T) {
+	enc := wave.NewEncoder(wave.NewExtensible(1, 8000, 16, wave.FormatPCM))
+	audio, err := enc.EncodeInt16([]int16{0, 1})
+	assertNoError(t, err)
+
+	// corrupts the SubFormat GUID (riff + fmt header + cbSize + ext fields)
+	audio[12+8+16+2+6+15] = 0
+	_, err = wave.DecodeHeader(bytes.NewReader(audio))
+	assertError(t, err)
+}
+
+func TestSubFormatGUID(t *testing.T) {
+	guid := wave.SubFormatGUID(wave.FormatIEEEFloat)
+	if s := guid.String(); s != "00000003-0000-0010-8000-00AA00389B71" {
+		t.Fatalf("unexpected GUID: %s", s)
+	}
+
+	format, ok := guid.Format()
+	if !ok || format != wave.FormatIEEEFloat {
+		t.Fatalf("unexpected format: %#x (%t)", format, ok)
+	}
+}
+
+func TestDecodeChunksRoundTrip(t *testing.T) {
+	audio, err := ioutil.ReadFile("testdata/r.wav")
+	assertNoError(t, err)
+
+	d := wave.NewDecoder(bytes.NewReader(audio))
+	var samples []int16
+	hdr, err := d.DecodeInt16(&samples)
+	assertNoError(t, err)
+
+	chunks := d.Chunks()
+	if len(chunks) != 1 || chunks[0].ID.String() != "LIST" {
+		t.Fatalf("unexpected chunks: %v", chunks)
+	}
+
+	enc := wave.NewEncoder(hdr)
+	enc.SetChunks(chunks)
+	got, err := enc.EncodeInt16(samples)
+	assertNoError(t, err)
+	assertBytesEqual(t, audio, got)
+}
+
+func TestDecodeChunksAfterData(t *testing.T) {
+	list := wave.Chunk{ID: riff.NewID("LIST"), Data: []byte("INFOodd")}
+	enc := wave.NewEncoder(wave.NewPCM(1, 8000, 8))
+	audio, err := enc.EncodeUint8([]uint8{1, 2, 3})
+	assertNoError(t, err)
+
+	// odd sized data chunk, followed by its pad byte and the chunks
+	var trailer bytes.Buffer
+	w := riff.NewWriter(&trailer)
+	assertNoError(t, w.WriteChunk(list.ID, list.Data))
+	assertNoError(t, w.WriteChunk(riff.NewID("fact"), []byte{3, 0, 0, 0}))
+	audio = append(audio, trailer.Bytes()...)
+
+	d := wave.NewDecoder(bytes.NewReader(audio))
+	d.Strict()
+	var samples []uint8
+	_, err = d.DecodeUint8(&samples)
+	assertNoError(t, err)
+	if !bytes.Equal(samples, []uint8{1, 2, 3}) {
+		t.Fatalf("unexpected samples: %v", samples)
+	}
+
+	chunks := d.Chunks()
+	if len(chunks) != 2 || !reflect.DeepEqual(chunks[0], list) ||
+		chunks[1].ID.String() != "fact" {
+		t.Fatalf("unexpected chunks: %v", chunks)
+	}
+}
+
+func TestEncodeOddChunk(t *testing.T) {
+	enc := wave.NewEncoder(wave.NewPCM(1, 8000, 16))
+	enc.SetChunks([]wave.Chunk{{ID: riff.NewID("note"), Data: []byte("abc")}})
+	audio, err := enc.EncodeInt16([]int16{1, 2})
+	assertNoError(t, err)
+
+	// 44 bytes of the header, chunk of 3 bytes padded and the samples
+	if len(audio) != 44+8+4+4 {
+		t.Fatalf("unexpected size: %d", len(audio))
+	}
+	if size := binary.LittleEndian.Uint32(audio[4:]); size != uint32(len(audio)-8) {
+		t.Fatalf("unexpected RIFF size: %d", size)
+	}
+
+	d := wave.NewDecoder(bytes.NewReader(audio))
+	d.Strict()
+	var samples []int16
+	_, err = d.DecodeInt16(&samples)
+	assertNoError(t, err)
+	if len(samples) != 2 || samples[1] != 2 {
+		t.Fatalf("unexpected samples: %v", samples)
+	}
+	if chunks := d.Chunks(); len(chunks) != 1 || string(chunks[0].Data) != "abc" {
+		t.Fatalf("unexpected chunks: %v", chunks)
+	}
+}
+
+func TestDecodeChunkBogusSize(t *testing.T) {
+	audio, err := wave.NewEncoder(wave.NewPCM(1, 8000, 16)).EncodeInt16([]int16{1, 2})
+	assertNoError(t, err)
+
+	// chunk after data declaring ~2GiB, that aren't in the file
+	var junk bytes.Buffer
+	assertNoError(t, riff.NewWriter(&junk).WriteHeader(riff.NewID("junk"), 0x7FFFFFF0))
+	junk.Write([]byte{1, 2, 3})
+	audio = append(audio, junk.Bytes()...)
+
+	d := wave.NewDecoder(bytes.NewReader(audio))
+	var samples []int16
+	_, err = d.DecodeInt16(&samples)
+	assertNoError(t, err)
+	if len(samples) != 2 {
+		t.Fatalf("unexpected samples: %v", samples)
+	}
+
+	if chunks := d.Chunks(); len(chunks) != 0 {
+		t.Fatalf("unexpected chunks: %v", chunks)
+	}
+	violations := d.Violations()
+	if len(violations) != 1 || violations[0].Field != "Chunks" {
+		t.Fatalf("unexpected violations: %v", violations)
+	}
+
+	// strict mode rejects it at the end of the data chunk
+	d = wave.NewDecoder(bytes.NewReader(audio))
+	d.Strict()
+	_, err = d.DecodeInt16(&samples)
+	assertError(t, err)
+
+	// the same chunk before data fails to decode, without allocating
+	// its size either
+	before := append([]byte{}, audio[:36]...)
+	before = append(before, junk.Bytes()...)
+	_, err = wave.DecodeHeader(bytes.NewReader(before))
+	assertError(t, err)
+}
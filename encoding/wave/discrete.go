@@ -0,0 +1,315 @@
+package wave
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/NeowayLabs/signal"
+	"github.com/NeowayLabs/signal/encoding/g711"
+)
+
+type (
+	// Quantization is the rounding method used to convert normalized
+	// samples into integer PCM samples.
+	Quantization int
+
+	// Dither is the kind of noise added to the samples before the
+	// quantization, to decorrelate the quantization error from the
+	// signal.
+	Dither int
+
+	// quantizer converts normalized samples into integers.
+	quantizer struct {
+		quantization Quantization
+		dither       Dither
+		rnd          *rand.Rand
+	}
+)
+
+const (
+	// Round rounds to the nearest integer.
+	Round Quantization = iota
+	// Truncate rounds down.
+	Truncate
+)
+
+const (
+	// NoDither disables dithering.
+	NoDither Dither = iota
+	// RectangularDither adds uniform noise of ±0.5 LSB (RPDF).
+	RectangularDither
+	// TriangularDither adds triangular noise of ±1 LSB (TPDF).
+	TriangularDither
+)
+
+// SetQuantization sets the rounding method used when encoding
+// normalized samples into integer PCM. The default is Round.
+func (q *quantizer) SetQuantization(quantization Quantization) {
+	q.quantization = quantization
+}
+
+// SetDither sets the dither added when encoding normalized samples
+// into integer PCM. The default is NoDither.
+// The noise generator has a fixed seed, then encoding the same samples
+// twice produces the same output.
+func (q *quantizer) SetDither(dither Dither) {
+	q.dither = dither
+}
+
+// quantize converts the normalized sample x into a signed integer of
+// the given bits, clipping it to the valid range.
+func (q *quantizer) quantize(x float64, bits uint) int64 {
+	scale := float64(int64(1) << (bits - 1))
+	v := x * scale
+
+	switch q.dither {
+	case RectangularDither:
+		v += q.random() - 0.5
+	case TriangularDither:
+		v += q.random() - q.random()
+	}
+
+	if q.quantization == Truncate {
+		v = math.Floor(v)
+	} else {
+		v = math.Floor(v + 0.5)
+	}
+
+	if v > scale-1 {
+		return int64(scale) - 1
+	}
+	if v < -scale {
+		return -int64(scale)
+	}
+	return int64(v)
+}
+
+func (q *quantizer) random() float64 {
+	if q.rnd == nil {
+		q.rnd = rand.New(rand.NewSource(1))
+	}
+	return q.rnd.Float64()
+}
+
+// sampleDecoder returns the size of each sample of hdr and a function
+// converting the raw sample into a normalized float64.
+func sampleDecoder(hdr Header, order binary.ByteOrder) (int, func([]byte) (float64, error), error) {
+	switch f := hdr.Format(); {
+	case f == FormatPCM && hdr.BitsPerSample == 8:
+		return 1, func(b []byte) (float64, error) {
+			return float64(int(b[0])-128) / 128, nil
+		}, nil
+	case f == FormatPCM && hdr.BitsPerSample == 16:
+		return 2, func(b []byte) (float64, error) {
+			return float64(int16(order.Uint16(b))) / (1 << 15), nil
+		}, nil
+	case f == FormatPCM && hdr.BitsPerSample == 24:
+		return 3, func(b []byte) (float64, error) {
+			return float64(int24(order, b)) / (1 << 23), nil
+		}, nil
+	case f == FormatPCM && hdr.BitsPerSample == 32:
+		return 4, func(b []byte) (float64, error) {
+			return float64(int32(order.Uint32(b))) / (1 << 31), nil
+		}, nil
+	case f == FormatALAW && hdr.BitsPerSample == 8:
+		return 1, func(b []byte) (float64, error) {
+			return float64(g711.DecodeALaw(b[0])) / (1 << 15), nil
+		}, nil
+	case f == FormatMULAW && hdr.BitsPerSample == 8:
+		return 1, func(b []byte) (float64, error) {
+			return float64(g711.DecodeMuLaw(b[0])) / (1 << 15), nil
+		}, nil
+	case f == FormatIEEEFloat && hdr.BitsPerSample == 32:
+		return 4, func(b []byte) (float64, error) {
+			sample := math.Float32frombits(order.Uint32(b))
+			if sample < -1 || sample > 1 {
+				return 0, fmt.Errorf(
+					"sample[%f] is outside the valid value range for a PCM float",
+					sample,
+				)
+			}
+			return float64(sample), nil
+		}, nil
+	}
+
+	return 0, nil, fmt.Errorf("unsupported audio format: format[%d], bits[%d]",
+		hdr.Format(), hdr.BitsPerSample)
+}
+
+// ReadDiscrete reads up to len(buf) samples from the data chunk,
+// whatever the audio format, normalizing them to the [-1.0, 1.0]
+// range. Multichannel samples are interleaved.
+// It returns the number of samples read and has the same semantics
+// of ReadInt16.
+func (d *Decoder) ReadDiscrete(buf signal.Discrete) (int, error) {
+	hdr, err := d.header()
+	if err != nil {
+		return 0, err
+	}
+
+	size, conv, err := sampleDecoder(hdr, d.byteOrder)
+	if err != nil {
+		return 0, fmt.Errorf("reading discrete: %s", err)
+	}
+
+	raw, err := d.readBlock(len(buf), size)
+
+	n := len(raw) / size
+	for i := 0; i < n; i++ {
+		sample, converr := conv(raw[size*i:])
+		if converr != nil {
+			return i, converr
+		}
+		buf[i] = sample
+	}
+	return n, err
+}
+
+// DecodeDiscrete decodes the WAV buffer of any supported audio format,
+// returning the wave header and appending the normalized samples of
+// each channel to the channels of data (that must be empty or have
+// the number of channels of the audio).
+// It has the same semantics of DecodeInt16.
+func (d *Decoder) DecodeDiscrete(data *[]signal.Discrete) (hdr Header, err error) {
+	var m signal.Multichannel
+	hdr, err = d.DecodeMultichannel(&m)
+
+	switch {
+	case len(*data) == 0:
+		*data = append(*data, m.Channels...)
+	case len(*data) != len(m.Channels):
+		return hdr, fmt.Errorf("decoding discrete: channels differ: %d != %d",
+			len(*data), len(m.Channels))
+	default:
+		for i, ch := range m.Channels {
+			(*data)[i] = append((*data)[i], ch...)
+		}
+	}
+	return hdr, err
+}
+
+// DecodeMultichannel decodes the WAV buffer of any supported audio
+// format, returning the wave header and filling m with the normalized
+// samples of each channel and the sample rate of the audio.
+// It has the same semantics of DecodeInt16.
+func (d *Decoder) DecodeMultichannel(m *signal.Multichannel) (hdr Header, err error) {
+	var samples signal.Discrete
+	buf := make(signal.Discrete, decodeBlockSize)
+	hdr, err = d.decode("discrete", func() (int, error) {
+		n, err := d.ReadDiscrete(buf)
+		samples = append(samples, buf[:n]...)
+		return n, err
+	})
+	if hdr.NumChannels == 0 {
+		return hdr, err
+	}
+
+	nchannels := int(hdr.NumChannels)
+	samples = samples[:len(samples)-len(samples)%nchannels]
+
+	var derr error
+	*m, derr = signal.Deinterleave(samples, nchannels, float64(hdr.SampleRate))
+	if err == nil {
+		err = derr
+	}
+	return hdr, err
+}
+
+// WriteDiscrete writes a block of normalized samples, converting
+// them into the audio format of the stream. Multichannel samples
+// must be interleaved.
+// Samples outside the [-1.0, 1.0] range are clipped.
+func (s *StreamEncoder) WriteDiscrete(data signal.Discrete) error {
+	bits := uint(s.hdr.BitsPerSample)
+
+	switch f := s.hdr.Format(); {
+	case f == FormatPCM && bits == 8:
+		samples := make([]uint8, len(data))
+		for i, v := range data {
+			samples[i] = uint8(s.quantize(v, bits) + 128)
+		}
+		return s.WriteUint8(samples)
+	case f == FormatPCM && bits == 16:
+		samples := make([]int16, len(data))
+		for i, v := range data {
+			samples[i] = int16(s.quantize(v, bits))
+		}
+		return s.WriteInt16(samples)
+	case (f == FormatALAW || f == FormatMULAW) && bits == 8:
+		// companding is done over 16 bit linear PCM
+		samples := make([]int16, len(data))
+		for i, v := range data {
+			samples[i] = int16(s.quantize(v, 16))
+		}
+		return s.WriteInt16(samples)
+	case f == FormatPCM && bits == 24:
+		samples := make([]int32, len(data))
+		for i, v := range data {
+			samples[i] = int32(s.quantize(v, bits))
+		}
+		return s.WriteInt24(samples)
+	case f == FormatPCM && bits == 32:
+		samples := make([]int32, len(data))
+		for i, v := range data {
+			samples[i] = int32(s.quantize(v, bits))
+		}
+		return s.WriteInt32(samples)
+	case f == FormatIEEEFloat && bits == 32:
+		samples := make([]float32, len(data))
+		for i, v := range data {
+			samples[i] = float32(math.Max(-1, math.Min(1, v)))
+		}
+		return s.WriteFloat32(samples)
+	}
+
+	return fmt.Errorf("writing discrete: unsupported audio format: format[%d], bits[%d]",
+		s.hdr.Format(), bits)
+}
+
+// EncodeDiscrete encodes the normalized samples of each channel as a
+// WAVE file in the audio format of the encoder's header.
+// All channels must have the same length.
+func (e *Encoder) EncodeDiscrete(data []signal.Discrete) ([]byte, error) {
+	if len(data) != int(e.hdr.NumChannels) {
+		return nil, fmt.Errorf("encoding discrete: expected %d channels, got %d",
+			e.hdr.NumChannels, len(data))
+	}
+
+	return e.EncodeMultichannel(signal.Multichannel{
+		SampleRate: float64(e.hdr.SampleRate),
+		Channels:   data,
+	})
+}
+
+// EncodeMultichannel encodes the normalized samples of m as a WAVE
+// file in the audio format of the encoder's header. The number of
+// channels and the sample rate of the file are taken from m (if the
+// SampleRate of m is zero, the rate of the header is used).
+// All channels must have the same length.
+func (e *Encoder) EncodeMultichannel(m signal.Multichannel) ([]byte, error) {
+	nframes := m.NumFrames()
+	for i, ch := range m.Channels {
+		if len(ch) != nframes {
+			return nil, fmt.Errorf("encoding discrete: channel[%d] length differs: %d != %d",
+				i, len(ch), nframes)
+		}
+	}
+
+	samplerate := int(e.hdr.SampleRate)
+	if m.SampleRate != 0 {
+		samplerate = int(math.Floor(m.SampleRate + 0.5))
+	}
+
+	enc := *e
+	err := enc.hdr.setLayout(m.NumChannels(), samplerate)
+	if err != nil {
+		return nil, fmt.Errorf("encoding multichannel: %s", err)
+	}
+
+	interleaved := m.Interleave()
+	return enc.encode(enc.dataSize(len(interleaved)), func(s *StreamEncoder) error {
+		return s.WriteDiscrete(interleaved)
+	})
+}
@@ -0,0 +1,234 @@
+package wave_test
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/NeowayLabs/signal"
+	"github.com/NeowayLabs/signal/encoding/wave"
+)
+
+func TestDecodeDiscrete(t *testing.T) {
+	f, err := os.Open("testdata/audios/sint16le.wav")
+	assertNoError(t, err)
+	defer f.Close()
+
+	expected := []int16{}
+	_, err = wave.NewDecoder(f).DecodeInt16(&expected)
+	assertNoError(t, err)
+
+	_, err = f.Seek(0, 0)
+	assertNoError(t, err)
+
+	var chans []signal.Discrete
+	hdr, err := wave.NewDecoder(f).DecodeDiscrete(&chans)
+	assertNoError(t, err)
+
+	if len(chans) != int(hdr.NumChannels) || len(chans[0]) != len(expected) {
+		t.Fatalf("unexpected channels: %d", len(chans))
+	}
+	for i, v := range expected {
+		if chans[0][i] != float64(v)/32768 {
+			t.Fatalf("sample[%d] differs: %f != %d", i, chans[0][i], v)
+		}
+	}
+}
+
+func TestDecodeDiscreteAppends(t *testing.T) {
+	enc := wave.NewEncoder(wave.NewPCM(2, 8000, 16))
+	audio, err := enc.EncodeInt16([]int16{16384, -16384})
+	assertNoError(t, err)
+
+	chans := []signal.Discrete{{1}, {-1}}
+	_, err = wave.NewDecoder(bytes.NewReader(audio)).DecodeDiscrete(&chans)
+	assertNoError(t, err)
+	if len(chans) != 2 || !reflect.DeepEqual(chans[0], signal.Discrete{1, 0.5}) ||
+		!reflect.DeepEqual(chans[1], signal.Discrete{-1, -0.5}) {
+		t.Fatalf("unexpected channels: %v", chans)
+	}
+
+	mono := []signal.Discrete{{1}}
+	_, err = wave.NewDecoder(bytes.NewReader(audio)).DecodeDiscrete(&mono)
+	assertError(t, err)
+}
+
+func TestEncodeDiscreteRoundTrip(t *testing.T) {
+	chans := []signal.Discrete{
+		{-1, -0.5, 0, 0.25, 0.5, 0.999},
+		{0.999, 0.5, 0.25, 0, -0.5, -1},
+	}
+
+	for _, hdr := range []wave.Header{
+		wave.NewPCM(2, 8000, 8),
+		wave.NewPCM(2, 8000, 16),
+		wave.NewPCM(2, 8000, 24),
+		wave.NewPCM(2, 8000, 32),
+		wave.NewIEEEFloat(2, 8000, 32),
+	} {
+		enc := wave.NewEncoder(hdr)
+		audio, err := enc.EncodeDiscrete(chans)
+		assertNoError(t, err)
+
+		var got []signal.Discrete
+		_, err = wave.NewDecoder(bytes.NewReader(audio)).DecodeDiscrete(&got)
+		assertNoError(t, err)
+
+		// one quantization step (or float32 precision)
+		ε := 1.0 / float64(int(1)<<(hdr.BitsPerSample-1))
+		if hdr.AudioFormat == wave.FormatIEEEFloat {
+			ε = 1e-7
+		}
+		for ch := range chans {
+			for i := range chans[ch] {
+				if !signal.Almost(got[ch][i], chans[ch][i], ε) {
+					t.Fatalf("%d bits: sample[%d][%d] differs: %f != %f",
+						hdr.BitsPerSample, ch, i, got[ch][i], chans[ch][i])
+				}
+			}
+		}
+	}
+}
+
+func TestEncodeDiscreteQuantization(t *testing.T) {
+	sig := []signal.Discrete{{0.7 / 128, -0.7 / 128, 2, -2}}
+
+	enc := wave.NewEncoder(wave.NewPCM(1, 8000, 8))
+	audio, err := enc.EncodeDiscrete(sig)
+	assertNoError(t, err)
+
+	got := []uint8{}
+	_, err = wave.NewDecoder(bytes.NewReader(audio)).DecodeUint8(&got)
+	assertNoError(t, err)
+	if !bytes.Equal(got, []uint8{129, 127, 255, 0}) {
+		t.Fatalf("rounded samples differs: %v", got)
+	}
+
+	enc.SetQuantization(wave.Truncate)
+	audio, err = enc.EncodeDiscrete(sig)
+	assertNoError(t, err)
+
+	got = []uint8{}
+	_, err = wave.NewDecoder(bytes.NewReader(audio)).DecodeUint8(&got)
+	assertNoError(t, err)
+	if !bytes.Equal(got, []uint8{128, 127, 255, 0}) {
+		t.Fatalf("truncated samples differs: %v", got)
+	}
+}
+
+func TestEncodeDiscreteDither(t *testing.T) {
+	sig := []signal.Discrete{make(signal.Discrete, 1000)}
+
+	enc := wave.NewEncoder(wave.NewPCM(1, 8000, 16))
+	enc.SetDither(wave.TriangularDither)
+
+	first, err := enc.EncodeDiscrete(sig)
+	assertNoError(t, err)
+	second, err := enc.EncodeDiscrete(sig)
+	assertNoError(t, err)
+
+	if !bytes.Equal(first, second) {
+		t.Fatal("dither isn't reproducible")
+	}
+
+	samples := []int16{}
+	_, err = wave.NewDecoder(bytes.NewReader(first)).DecodeInt16(&samples)
+	assertNoError(t, err)
+
+	var nonzero int
+	for _, v := range samples {
+		if v < -1 || v > 1 {
+			t.Fatalf("dither noise too high: %d", v)
+		}
+		if v != 0 {
+			nonzero++
+		}
+	}
+	if nonzero == 0 {
+		t.Fatal("no dither noise added")
+	}
+}
+
+func TestEncodeDiscreteChannelMismatch(t *testing.T) {
+	enc := wave.NewEncoder(wave.NewPCM(2, 8000, 16))
+	_, err := enc.EncodeDiscrete([]signal.Discrete{{0, 1}})
+	assertError(t, err)
+
+	_, err = enc.EncodeDiscrete([]signal.Discrete{{0, 1}, {0}})
+	assertError(t, err)
+}
+
+func TestEncodeMultichannel(t *testing.T) {
+	m := signal.NewMultichannel(3, 4, 16000)
+	for ch := range m.Channels {
+		for i := range m.Channels[ch] {
+			m.Channels[ch][i] = float64(ch+1) * float64(i) / 16
+		}
+	}
+
+	enc := wave.NewEncoder(wave.NewPCM(1, 8000, 16))
+	audio, err := enc.EncodeMultichannel(m)
+	assertNoError(t, err)
+
+	var got signal.Multichannel
+	hdr, err := wave.NewDecoder(bytes.NewReader(audio)).DecodeMultichannel(&got)
+	assertNoError(t, err)
+
+	if hdr.NumChannels != 3 || hdr.SampleRate != 16000 ||
+		hdr.BytesPerBloc != 6 || hdr.BytesPerSec != 6*16000 {
+		t.Fatalf("unexpected header: %#v", hdr)
+	}
+	if got.SampleRate != 16000 || got.NumChannels() != 3 || got.NumFrames() != 4 {
+		t.Fatalf("unexpected signal: %#v", got)
+	}
+	for ch := range m.Channels {
+		for i := range m.Channels[ch] {
+			if !signal.Almost(got.Channels[ch][i], m.Channels[ch][i], 1.0/32768) {
+				t.Fatalf("sample[%d][%d] differs", ch, i)
+			}
+		}
+	}
+}
+
+func TestEncodeMultichannelInvalidLayout(t *testing.T) {
+	enc := wave.NewEncoder(wave.NewIEEEFloat(1, 8000, 32))
+
+	// block size of 16384 channels of 32 bits overflows BytesPerBloc
+	_, err := enc.EncodeMultichannel(signal.NewMultichannel(16384, 1, 8000))
+	assertError(t, err)
+
+	_, err = enc.EncodeMultichannel(signal.NewMultichannel(0, 0, 8000))
+	assertError(t, err)
+
+	// byte rate overflows BytesPerSec
+	_, err = enc.EncodeMultichannel(signal.NewMultichannel(2, 1, 1<<30))
+	assertError(t, err)
+}
+
+func TestResampleReencode(t *testing.T) {
+	f, err := os.Open("testdata/audios/sint16le.wav")
+	assertNoError(t, err)
+	defer f.Close()
+
+	var m signal.Multichannel
+	hdr, err := wave.NewDecoder(f).DecodeMultichannel(&m)
+	assertNoError(t, err)
+
+	resampled, err := m.Resample(2*m.SampleRate, signal.QualityMedium)
+	assertNoError(t, err)
+
+	audio, err := wave.NewEncoder(hdr).EncodeMultichannel(resampled)
+	assertNoError(t, err)
+
+	var got signal.Multichannel
+	newhdr, err := wave.NewDecoder(bytes.NewReader(audio)).DecodeMultichannel(&got)
+	assertNoError(t, err)
+
+	if newhdr.SampleRate != 2*hdr.SampleRate || newhdr.BytesPerSec != 2*hdr.BytesPerSec {
+		t.Fatalf("unexpected header: %#v", newhdr)
+	}
+	if got.NumFrames() != 2*m.NumFrames() {
+		t.Fatalf("unexpected number of frames: %d != %d", got.NumFrames(), 2*m.NumFrames())
+	}
+}
@@ -3,16 +3,24 @@ package wave
 import (
 	"bytes"
 	"encoding/binary"
+
+	"github.com/NeowayLabs/signal/encoding/riff"
 )
 
 const (
-	waveHdrSize = 44 // Riff header + FmtChunk
+	waveHdrSize = 44 // Riff header + FmtChunk + data chunk header
 )
 
 // Encoder of WAVE audio format
 type Encoder struct {
 	hdr       Header           // hdr of output WAV
 	byteOrder binary.ByteOrder // encoder's byte order for data samples
+	chunks    []Chunk          // chunks written before the data chunk
+	info      *metadata        // set by SetInfo
+	bext      *metadata        // set by SetBext
+	ixml      *metadata        // set by SetIXML
+
+	quantizer
 }
 
 // NewEncoder creates a new encoder for header hdr.
@@ -26,99 +34,144 @@ func NewEncoder(hdr Header) *Encoder {
 	}
 }
 
-func (e *Encoder) writeHeader(buf *bytes.Buffer, datasz uint32) error {
-	lewrite := func(d interface{}) error {
-		return binary.Write(buf, binary.LittleEndian, d)
-	}
+// SetChunks sets the chunks written between the fmt and the data
+// chunks, like the ones of a decoded file (see Decoder.Chunks).
+func (e *Encoder) SetChunks(chunks []Chunk) {
+	e.chunks = chunks
+}
 
-	err := lewrite(e.hdr.RiffHeader.Ident)
-	if err != nil {
-		return err
-	}
+// dataSize returns the size in bytes of nsamples samples.
+func (e *Encoder) dataSize(nsamples int) int {
+	return nsamples * ((int(e.hdr.BitsPerSample) + 7) / 8)
+}
 
-	err = lewrite(uint32(waveHdrSize + 8 + datasz)) // 8 = ChID+CkSize, 2 = sizeof(int16)
-	if err != nil {
-		return err
-	}
+// metadata is a chunk set by SetInfo, SetBext or SetIXML, replacing
+// the chunks matching.
+type metadata struct {
+	match func(Chunk) bool
+	chunk func() (Chunk, error) // nil removes the chunks matching
+}
 
-	err = lewrite(e.hdr.RiffHeader.FileType)
-	if err != nil {
-		return err
+// allChunks returns the chunks given to SetChunks with the metadata
+// chunks replaced by the ones set with SetInfo, SetBext and SetIXML.
+func (e *Encoder) allChunks() ([]Chunk, error) {
+	chunks := e.chunks
+	for _, m := range []*metadata{e.info, e.bext, e.ixml} {
+		if m == nil {
+			continue
+		}
+
+		var c *Chunk
+		if m.chunk != nil {
+			chunk, err := m.chunk()
+			if err != nil {
+				return nil, err
+			}
+			c = &chunk
+		}
+		chunks = replaceChunks(chunks, m.match, c)
 	}
+	return chunks, nil
+}
 
-	err = lewrite([4]byte{'f', 'm', 't', ' '})
-	if err != nil {
-		return err
+// replaceChunks returns the chunks with the ones matching replaced by
+// c, in the place of the first one (or at the end). The matching
+// chunks are just removed if c is nil.
+func replaceChunks(chunks []Chunk, match func(Chunk) bool, c *Chunk) []Chunk {
+	out := make([]Chunk, 0, len(chunks)+1)
+	written := c == nil
+	for _, chunk := range chunks {
+		if !match(chunk) {
+			out = append(out, chunk)
+			continue
+		}
+		if !written {
+			out = append(out, *c)
+			written = true
+		}
 	}
+	if !written {
+		out = append(out, *c)
+	}
+	return out
+}
 
-	return lewrite(e.hdr.RiffChunkFmt)
+// isChunk returns a function matching the chunks with the id.
+func isChunk(id riff.ID) func(Chunk) bool {
+	return func(c Chunk) bool {
+		return c.ID == id
+	}
 }
 
-func (e *Encoder) EncodeInt16(data []int16) ([]byte, error) {
-	buf := bytes.NewBuffer(make([]byte, 0, waveHdrSize))
+// encode writes a whole WAVE file with datasz bytes of samples into
+// memory. The samples are written by the write callback using the
+// provided stream encoder.
+func (e *Encoder) encode(datasz int, write func(s *StreamEncoder) error) ([]byte, error) {
+	buf := bytes.NewBuffer(make([]byte, 0, waveHdrSize+datasz))
 
-	lewrite := func(d interface{}) error {
-		return binary.Write(buf, binary.LittleEndian, d)
-	}
+	hdr := e.hdr
+	hdr.DataBlockSize = uint32(datasz)
 
-	err := e.writeHeader(buf, uint32(len(data)*2))
+	chunks, err := e.allChunks()
 	if err != nil {
 		return nil, err
 	}
 
-	err = lewrite([4]byte{'d', 'a', 't', 'a'})
+	s, err := newStreamEncoder(buf, hdr, true, chunks)
 	if err != nil {
 		return nil, err
 	}
+	s.byteOrder = e.byteOrder
+	s.quantizer = e.quantizer
 
-	// chunk size
-	err = lewrite(uint32(2 * len(data)))
+	err = write(s)
 	if err != nil {
 		return nil, err
 	}
 
-	// write data
-	for _, d := range data {
-		err := lewrite(d)
-		if err != nil {
-			return nil, err
-		}
+	err = s.Close()
+	if err != nil {
+		return nil, err
 	}
 
 	return buf.Bytes(), nil
 }
 
-func (e *Encoder) EncodeFloat32(data []float32) ([]byte, error) {
-	buf := bytes.NewBuffer(make([]byte, 0, waveHdrSize))
-	datasz := uint32(4 * len(data))
-
-	lewrite := func(d interface{}) error {
-		return binary.Write(buf, binary.LittleEndian, d)
-	}
-
-	err := e.writeHeader(buf, datasz)
-	if err != nil {
-		return nil, err
-	}
+// EncodeUint8 encodes the 8 bit unsigned samples in data as a WAVE
+// file.
+func (e *Encoder) EncodeUint8(data []uint8) ([]byte, error) {
+	return e.encode(e.dataSize(len(data)), func(s *StreamEncoder) error {
+		return s.WriteUint8(data)
+	})
+}
 
-	err = lewrite([4]byte{'d', 'a', 't', 'a'})
-	if err != nil {
-		return nil, err
-	}
+// EncodeInt16 encodes the 16 bit samples in data as a WAVE file.
+// If the header is of A-law or μ-law format, the samples are
+// compressed.
+func (e *Encoder) EncodeInt16(data []int16) ([]byte, error) {
+	return e.encode(e.dataSize(len(data)), func(s *StreamEncoder) error {
+		return s.WriteInt16(data)
+	})
+}
 
-	// chunk size
-	err = lewrite(datasz)
-	if err != nil {
-		return nil, err
-	}
+// EncodeInt24 encodes the samples in data as packed 24 bit integers
+// in a WAVE file.
+func (e *Encoder) EncodeInt24(data []int32) ([]byte, error) {
+	return e.encode(e.dataSize(len(data)), func(s *StreamEncoder) error {
+		return s.WriteInt24(data)
+	})
+}
 
-	// write data
-	for _, d := range data {
-		err := lewrite(d)
-		if err != nil {
-			return nil, err
-		}
-	}
+// EncodeInt32 encodes the 32 bit samples in data as a WAVE file.
+func (e *Encoder) EncodeInt32(data []int32) ([]byte, error) {
+	return e.encode(e.dataSize(len(data)), func(s *StreamEncoder) error {
+		return s.WriteInt32(data)
+	})
+}
 
-	return buf.Bytes(), nil
+// EncodeFloat32 encodes the float samples in data as a WAVE file.
+func (e *Encoder) EncodeFloat32(data []float32) ([]byte, error) {
+	return e.encode(e.dataSize(len(data)), func(s *StreamEncoder) error {
+		return s.WriteFloat32(data)
+	})
 }
@@ -6,6 +6,8 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/NeowayLabs/signal"
+	"github.com/NeowayLabs/signal/encoding/g711"
 	"github.com/NeowayLabs/signal/encoding/wave"
 )
 
@@ -46,3 +48,171 @@ func TestEncoder(t *testing.T) {
 	// 	t.Fatalf("headers differs: %v != %v", gotHdr, expectedHdr)
 	// }
 }
+
+func TestEncoderEmpty(t *testing.T) {
+	enc := wave.NewEncoder(wave.NewPCM(1, 8000, 16))
+	audio, err := enc.EncodeInt16(nil)
+	assertNoError(t, err)
+
+	hdr, err := wave.DecodeHeader(bytes.NewReader(audio))
+	assertNoError(t, err)
+	if len(audio) != 44 || hdr.RiffHeader.ChunkSize != 36 || hdr.DataBlockSize != 0 {
+		t.Fatalf("unexpected sizes: riff[%d] data[%d]",
+			hdr.RiffHeader.ChunkSize, hdr.DataBlockSize)
+	}
+}
+
+func TestEncoderExtensible(t *testing.T) {
+	samples := []int16{
+		1, 2, 3, 4,
+		-1, -2, -3, -4,
+	}
+	enc := wave.NewEncoder(wave.NewPCM(4, 8000, 16))
+	audio, err := enc.EncodeInt16(samples)
+	assertNoError(t, err)
+
+	d := wave.NewDecoder(bytes.NewReader(audio))
+	got := []int16{}
+	hdr, err := d.DecodeInt16(&got)
+	assertNoError(t, err)
+
+	if hdr.AudioFormat != wave.FormatExtensible {
+		t.Fatalf("expected extensible format, got %#x", hdr.AudioFormat)
+	}
+	if hdr.Format() != wave.FormatPCM {
+		t.Fatalf("expected PCM subformat, got %#x", hdr.Format())
+	}
+	if hdr.Extension.SubFormat != wave.SubFormatGUID(wave.FormatPCM) {
+		t.Fatalf("unexpected subformat: %s", hdr.Extension.SubFormat)
+	}
+	if hdr.Extension.ValidBitsPerSample != 16 || hdr.Extension.ChannelMask != 0xF {
+		t.Fatalf("unexpected fmt extension: %#v", hdr.Extension)
+	}
+	if !reflect.DeepEqual(got, samples) {
+		t.Fatalf("samples differs: %v != %v", got, samples)
+	}
+}
+
+func TestEncoderIntegerPCM(t *testing.T) {
+	uint8s := []uint8{0, 1, 127, 128, 255}
+	int24s := []int32{-8388608, -1, 0, 1, 0x123456, 8388607}
+	int32s := []int32{-2147483648, -1, 0, 1, 2147483647}
+
+	t.Run("uint8", func(t *testing.T) {
+		enc := wave.NewEncoder(wave.NewPCM(1, 8000, 8))
+		audio, err := enc.EncodeUint8(uint8s)
+		assertNoError(t, err)
+
+		got := []uint8{}
+		hdr, err := wave.NewDecoder(bytes.NewReader(audio)).DecodeUint8(&got)
+		assertNoError(t, err)
+		if hdr.BytesPerBloc != 1 || hdr.DataBlockSize != 5 {
+			t.Fatalf("unexpected header: %#v", hdr)
+		}
+		if !reflect.DeepEqual(got, uint8s) {
+			t.Fatalf("samples differs: %v != %v", got, uint8s)
+		}
+	})
+
+	t.Run("int24", func(t *testing.T) {
+		enc := wave.NewEncoder(wave.NewPCM(2, 48000, 24))
+		audio, err := enc.EncodeInt24(int24s)
+		assertNoError(t, err)
+
+		got := []int32{}
+		hdr, err := wave.NewDecoder(bytes.NewReader(audio)).DecodeInt24(&got)
+		assertNoError(t, err)
+		if hdr.BytesPerBloc != 6 || hdr.BytesPerSec != 6*48000 {
+			t.Fatalf("unexpected header: %#v", hdr)
+		}
+		if !reflect.DeepEqual(got, int24s) {
+			t.Fatalf("samples differs: %v != %v", got, int24s)
+		}
+	})
+
+	t.Run("int32", func(t *testing.T) {
+		enc := wave.NewEncoder(wave.NewPCM(1, 96000, 32))
+		audio, err := enc.EncodeInt32(int32s)
+		assertNoError(t, err)
+
+		got := []int32{}
+		hdr, err := wave.NewDecoder(bytes.NewReader(audio)).DecodeInt32(&got)
+		assertNoError(t, err)
+		if hdr.BytesPerBloc != 4 || hdr.Format() != wave.FormatPCM {
+			t.Fatalf("unexpected header: %#v", hdr)
+		}
+		if !reflect.DeepEqual(got, int32s) {
+			t.Fatalf("samples differs: %v != %v", got, int32s)
+		}
+	})
+}
+
+func TestEncoderFormatMismatch(t *testing.T) {
+	enc := wave.NewEncoder(wave.NewPCM(1, 8000, 24))
+	_, err := enc.EncodeInt16([]int16{0})
+	assertError(t, err)
+
+	audio, err := enc.EncodeInt24([]int32{0})
+	assertNoError(t, err)
+
+	_, err = wave.NewDecoder(bytes.NewReader(audio)).DecodeInt32(&[]int32{})
+	assertError(t, err)
+}
+
+func TestEncoderG711(t *testing.T) {
+	samples := []int16{-32768, -1000, -1, 0, 1, 1000, 32767}
+
+	for _, tc := range []struct {
+		hdr    wave.Header
+		encode func(int16) uint8
+		decode func(uint8) int16
+	}{
+		{wave.NewALaw(1, 8000), g711.EncodeALaw, g711.DecodeALaw},
+		{wave.NewMuLaw(1, 8000), g711.EncodeMuLaw, g711.DecodeMuLaw},
+	} {
+		enc := wave.NewEncoder(tc.hdr)
+		audio, err := enc.EncodeInt16(samples)
+		assertNoError(t, err)
+
+		got := []int16{}
+		hdr, err := wave.NewDecoder(bytes.NewReader(audio)).DecodeInt16(&got)
+		assertNoError(t, err)
+
+		if hdr.DataBlockSize != uint32(len(samples)) || hdr.BytesPerBloc != 1 {
+			t.Fatalf("format %d: unexpected header: %#v", hdr.AudioFormat, hdr)
+		}
+		for i, v := range samples {
+			if expected := tc.decode(tc.encode(v)); got[i] != expected {
+				t.Fatalf("format %d: sample[%d] differs: %d != %d",
+					hdr.AudioFormat, i, got[i], expected)
+			}
+		}
+
+		var chans []signal.Discrete
+		_, err = wave.NewDecoder(bytes.NewReader(audio)).DecodeDiscrete(&chans)
+		assertNoError(t, err)
+		for i, v := range got {
+			if chans[0][i] != float64(v)/32768 {
+				t.Fatalf("format %d: discrete sample[%d] differs", hdr.AudioFormat, i)
+			}
+		}
+	}
+}
+
+func TestHeaderSetSampleRate(t *testing.T) {
+	hdr := wave.NewPCM(2, 44100, 16)
+	assertNoError(t, hdr.SetSampleRate(48000))
+
+	if hdr.SampleRate != 48000 || hdr.BytesPerSec != 4*48000 ||
+		hdr.BytesPerBloc != 4 || hdr.NumChannels != 2 {
+		t.Fatalf("unexpected header: %#v", hdr)
+	}
+
+	assertError(t, hdr.SetSampleRate(0))
+	assertError(t, hdr.SetSampleRate(1<<31))
+
+	// block size overflows BytesPerBloc
+	hdr = wave.NewIEEEFloat(1, 8000, 32)
+	hdr.NumChannels = 16384
+	assertError(t, hdr.SetSampleRate(8000))
+}
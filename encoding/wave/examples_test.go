@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"fmt"
 
+	"github.com/NeowayLabs/signal"
 	"github.com/NeowayLabs/signal/encoding/wave"
+	"github.com/NeowayLabs/signal/generator"
 )
 
 func ExampleDecodeHeader() {
@@ -29,3 +31,44 @@ func ExampleDecodeHeader() {
 	// Number of channels: 1
 	// Bytes/block: 2
 }
+
+func ExampleDecoder_DecodeDiscrete() {
+	tone, err := generator.Sine(generator.Params{
+		SampleRate: 8000,
+		Duration:   0.1,
+		Amplitude:  0.5,
+	}, 1000)
+	if err != nil {
+		panic(err)
+	}
+	enc := wave.NewEncoder(wave.NewPCM(1, 8000, 16))
+	audio, err := enc.EncodeDiscrete([]signal.Discrete{tone})
+	if err != nil {
+		panic(err)
+	}
+
+	var chans []signal.Discrete
+	hdr, err := wave.NewDecoder(bytes.NewReader(audio)).DecodeDiscrete(&chans)
+	if err != nil {
+		panic(err)
+	}
+
+	// spectrogram with the sample rate of the file
+	sp, err := signal.NewSTFT(256, 128, float64(hdr.SampleRate)).Transform(chans[0])
+	if err != nil {
+		panic(err)
+	}
+
+	frame := sp.Magnitudes()[2]
+	peak := 0
+	for k, v := range frame {
+		if v > frame[peak] {
+			peak = k
+		}
+	}
+	fmt.Printf("Frames: %d\n", len(sp.Frames))
+	fmt.Printf("Peak: %.0fhz\n", sp.Frequencies()[peak])
+
+	// Output: Frames: 8
+	// Peak: 1000hz
+}
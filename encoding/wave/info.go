@@ -0,0 +1,157 @@
+package wave
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/NeowayLabs/signal/encoding/riff"
+)
+
+// Info is the metadata of the LIST chunk of type INFO, where each tag
+// is a subchunk with a NUL terminated text.
+type Info struct {
+	Name         string // INAM: title of the subject of the file
+	Artist       string // IART: original artist of the subject
+	Comment      string // ICMT: general comments
+	CreationDate string // ICRD: creation date, eg. 2017-03-21
+	Software     string // ISFT: software used to create the file
+
+	// Extra are the other tags, by their IDs.
+	Extra map[riff.ID]string
+}
+
+var infoID = riff.NewID("INFO")
+
+// infoField is a known tag of the info.
+type infoField struct {
+	id    riff.ID
+	value *string
+}
+
+// fields returns the known tags of the info, in the order they're
+// written.
+func (i *Info) fields() []infoField {
+	return []infoField{
+		{riff.NewID("INAM"), &i.Name},
+		{riff.NewID("IART"), &i.Artist},
+		{riff.NewID("ICMT"), &i.Comment},
+		{riff.NewID("ICRD"), &i.CreationDate},
+		{riff.NewID("ISFT"), &i.Software},
+	}
+}
+
+// Empty tells if the info has no tags.
+func (i Info) Empty() bool {
+	for _, f := range i.fields() {
+		if *f.value != "" {
+			return false
+		}
+	}
+	for _, v := range i.Extra {
+		if v != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Chunk returns the LIST chunk with the tags of the info, that could
+// be given to NewStreamEncoder. The empty tags are omitted.
+func (i Info) Chunk() Chunk {
+	var buf bytes.Buffer
+	buf.Write(infoID[:])
+
+	// writing into a bytes.Buffer never fails
+	w := riff.NewWriter(&buf)
+	write := func(id riff.ID, value string) {
+		if value != "" {
+			_ = w.WriteChunk(id, append([]byte(value), 0))
+		}
+	}
+
+	for _, f := range i.fields() {
+		write(f.id, *f.value)
+	}
+
+	ids := make([]riff.ID, 0, len(i.Extra))
+	for id := range i.Extra {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(a, b int) bool {
+		return bytes.Compare(ids[a][:], ids[b][:]) < 0
+	})
+	for _, id := range ids {
+		write(id, i.Extra[id])
+	}
+
+	return Chunk{ID: riff.LIST, Data: buf.Bytes()}
+}
+
+// isInfo tells if the chunk is a LIST chunk of type INFO.
+func isInfo(c Chunk) bool {
+	return c.ID == riff.LIST && len(c.Data) >= 4 && bytes.Equal(c.Data[:4], infoID[:])
+}
+
+// parseInfo parses the tags of the LIST/INFO chunk c. The tags before
+// a malformed subchunk are returned with the error.
+func parseInfo(c Chunk) (Info, error) {
+	var info Info
+	known := info.fields()
+
+	tags := riff.NewReader(bytes.NewReader(c.Data[4:]))
+	for {
+		tag, err := tags.Next()
+		if err == io.EOF {
+			return info, nil
+		}
+		if err != nil {
+			return info, fmt.Errorf("parsing INFO: %s", err)
+		}
+		data, err := tag.Bytes()
+		if err != nil {
+			return info, fmt.Errorf("parsing INFO: %s", err)
+		}
+		value := strings.TrimRight(string(data), "\x00")
+
+		found := false
+		for _, f := range known {
+			if f.id == tag.ID {
+				*f.value = value
+				found = true
+			}
+		}
+		if !found {
+			if info.Extra == nil {
+				info.Extra = make(map[riff.ID]string)
+			}
+			info.Extra[tag.ID] = value
+		}
+	}
+}
+
+// Info returns the metadata of the first LIST/INFO chunk (see Chunks),
+// or nil if there's none. If the chunk is malformed, the tags read
+// before the error are returned with it.
+func (d *Decoder) Info() (*Info, error) {
+	c, ok := d.chunk(isInfo)
+	if !ok {
+		return nil, nil
+	}
+	info, err := parseInfo(c)
+	return &info, err
+}
+
+// SetInfo sets the metadata written as a LIST/INFO chunk, replacing
+// the LIST/INFO chunks given to SetChunks. A nil info removes them.
+func (e *Encoder) SetInfo(info *Info) {
+	e.info = &metadata{match: isInfo}
+	if info != nil {
+		i := *info
+		e.info.chunk = func() (Chunk, error) {
+			return i.Chunk(), nil
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package wave_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/NeowayLabs/signal/encoding/riff"
+	"github.com/NeowayLabs/signal/encoding/wave"
+)
+
+func decodeInfo(t *testing.T, audio []byte) (*wave.Info, []wave.Chunk) {
+	t.Helper()
+	d := decodeChunks(t, audio)
+	info, err := d.Info()
+	assertNoError(t, err)
+	return info, d.Chunks()
+}
+
+func TestInfoTestdata(t *testing.T) {
+	audio, err := ioutil.ReadFile("testdata/r.wav")
+	assertNoError(t, err)
+
+	d := wave.NewDecoder(bytes.NewReader(audio))
+	var samples []int16
+	hdr, err := d.DecodeInt16(&samples)
+	assertNoError(t, err)
+
+	info, err := d.Info()
+	assertNoError(t, err)
+	if info == nil || !reflect.DeepEqual(*info, wave.Info{Software: "Lavf56.40.101"}) {
+		t.Fatalf("unexpected info: %#v", info)
+	}
+
+	// writing the same info gives the same file
+	enc := wave.NewEncoder(hdr)
+	enc.SetChunks(d.Chunks())
+	enc.SetInfo(info)
+	got, err := enc.EncodeInt16(samples)
+	assertNoError(t, err)
+	assertBytesEqual(t, audio, got)
+}
+
+func TestInfoRoundTrip(t *testing.T) {
+	expected := wave.Info{
+		Name:         "call 4242",
+		Artist:       "agent 7",
+		Comment:      "odd",
+		CreationDate: "2017-03-21",
+		Software:     "signal",
+		Extra: map[riff.ID]string{
+			riff.NewID("ICOP"): "NeowayLabs",
+			riff.NewID("IENG"): "ops",
+		},
+	}
+
+	enc := wave.NewEncoder(wave.NewPCM(1, 8000, 16))
+	enc.SetChunks([]wave.Chunk{{ID: riff.NewID("fact"), Data: []byte{2, 0, 0, 0}}})
+	enc.SetInfo(&expected)
+	audio, err := enc.EncodeInt16([]int16{1, 2})
+	assertNoError(t, err)
+
+	info, chunks := decodeInfo(t, audio)
+	if info == nil || !reflect.DeepEqual(*info, expected) {
+		t.Fatalf("unexpected info: %#v", info)
+	}
+	if len(chunks) != 2 || chunks[0].ID.String() != "fact" {
+		t.Fatalf("unexpected chunks: %v", chunks)
+	}
+
+	// updating the info replaces the decoded LIST/INFO chunk
+	expected.Comment = "updated"
+	enc.SetChunks(chunks)
+	enc.SetInfo(&expected)
+	audio, err = enc.EncodeInt16([]int16{1, 2})
+	assertNoError(t, err)
+
+	info, chunks = decodeInfo(t, audio)
+	if info.Comment != "updated" || len(chunks) != 2 {
+		t.Fatalf("unexpected info: %#v, chunks: %v", info, chunks)
+	}
+}
+
+func TestInfoRemove(t *testing.T) {
+	enc := wave.NewEncoder(wave.NewPCM(1, 8000, 16))
+	enc.SetInfo(&wave.Info{Name: "name"})
+	audio, err := enc.EncodeInt16([]int16{1})
+	assertNoError(t, err)
+
+	_, chunks := decodeInfo(t, audio)
+	enc.SetChunks(chunks)
+	enc.SetInfo(nil)
+	audio, err = enc.EncodeInt16([]int16{1})
+	assertNoError(t, err)
+
+	info, chunks := decodeInfo(t, audio)
+	if info != nil || len(chunks) != 0 || len(audio) != 46 {
+		t.Fatalf("info not removed: chunks[%v] size[%d]", chunks, len(audio))
+	}
+}
+
+func TestInfoMalformed(t *testing.T) {
+	list := wave.Info{Name: "name", Artist: "artist"}.Chunk()
+	// truncates the IART tag
+	list.Data = list.Data[:len(list.Data)-4]
+
+	enc := wave.NewEncoder(wave.NewPCM(1, 8000, 16))
+	enc.SetChunks([]wave.Chunk{list})
+	audio, err := enc.EncodeInt16([]int16{1})
+	assertNoError(t, err)
+
+	info, err := decodeChunks(t, audio).Info()
+	assertError(t, err)
+	if info == nil || !reflect.DeepEqual(*info, wave.Info{Name: "name"}) {
+		t.Fatalf("unexpected info: %#v", info)
+	}
+}
@@ -0,0 +1,126 @@
+package wave
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+
+	"github.com/NeowayLabs/signal/encoding/riff"
+)
+
+type (
+	// IXML is the metadata of the iXML chunk, an XML document with
+	// the production information of the recording (see ixml.info).
+	// The elements without a field are kept in Extra, only the ones
+	// at the top level of the document.
+	IXML struct {
+		XMLName xml.Name `xml:"BWFXML"`
+
+		Version    string `xml:"IXML_VERSION,omitempty"`
+		Project    string `xml:"PROJECT,omitempty"`
+		Scene      string `xml:"SCENE,omitempty"`
+		Take       string `xml:"TAKE,omitempty"`
+		Tape       string `xml:"TAPE,omitempty"`
+		TakeType   string `xml:"TAKE_TYPE,omitempty"`
+		NoGood     string `xml:"NO_GOOD,omitempty"`     // TRUE or FALSE
+		FalseStart string `xml:"FALSE_START,omitempty"` // TRUE or FALSE
+		WildTrack  string `xml:"WILD_TRACK,omitempty"`  // TRUE or FALSE
+		Circled    string `xml:"CIRCLED,omitempty"`     // TRUE or FALSE
+		FileUID    string `xml:"FILE_UID,omitempty"`
+		UBits      string `xml:"UBITS,omitempty"`
+		Note       string `xml:"NOTE,omitempty"`
+
+		Speed     *IXMLSpeed     `xml:"SPEED,omitempty"`
+		TrackList *IXMLTrackList `xml:"TRACK_LIST,omitempty"`
+
+		Extra []IXMLElement `xml:",any"`
+	}
+
+	// IXMLSpeed is the SPEED element of the iXML, describing the
+	// timecode and the sample rates of the recording.
+	IXMLSpeed struct {
+		Note                string `xml:"NOTE,omitempty"`
+		MasterSpeed         string `xml:"MASTER_SPEED,omitempty"`  // eg. 25/1
+		CurrentSpeed        string `xml:"CURRENT_SPEED,omitempty"` // eg. 25/1
+		TimecodeRate        string `xml:"TIMECODE_RATE,omitempty"` // eg. 25/1
+		TimecodeFlag        string `xml:"TIMECODE_FLAG,omitempty"` // DF or NDF
+		FileSampleRate      int    `xml:"FILE_SAMPLE_RATE,omitempty"`
+		AudioBitDepth       int    `xml:"AUDIO_BIT_DEPTH,omitempty"`
+		DigitizerSampleRate int    `xml:"DIGITIZER_SAMPLE_RATE,omitempty"`
+
+		// timestamp of the first sample, as the number of samples
+		// since midnight at TimestampSampleRate.
+		TimestampHi         uint32 `xml:"TIMESTAMP_SAMPLES_SINCE_MIDNIGHT_HI"`
+		TimestampLo         uint32 `xml:"TIMESTAMP_SAMPLES_SINCE_MIDNIGHT_LO"`
+		TimestampSampleRate int    `xml:"TIMESTAMP_SAMPLE_RATE,omitempty"`
+	}
+
+	// IXMLTrackList is the TRACK_LIST element of the iXML.
+	IXMLTrackList struct {
+		Count  int         `xml:"TRACK_COUNT"`
+		Tracks []IXMLTrack `xml:"TRACK"`
+	}
+
+	// IXMLTrack describes a channel of the file.
+	IXMLTrack struct {
+		ChannelIndex    int    `xml:"CHANNEL_INDEX"`
+		InterleaveIndex int    `xml:"INTERLEAVE_INDEX"`
+		Name            string `xml:"NAME,omitempty"`
+		Function        string `xml:"FUNCTION,omitempty"`
+	}
+
+	// IXMLElement is an element of the iXML without a field, kept
+	// verbatim.
+	IXMLElement struct {
+		XMLName xml.Name
+		Content string `xml:",innerxml"`
+	}
+)
+
+var ixmlID = riff.NewID("iXML")
+
+// Chunk returns the iXML chunk, that could be given to
+// NewStreamEncoder.
+func (x IXML) Chunk() (Chunk, error) {
+	doc, err := xml.Marshal(x)
+	if err != nil {
+		return Chunk{}, fmt.Errorf("encoding iXML: %s", err)
+	}
+	data := append([]byte(xml.Header), doc...)
+	return Chunk{ID: ixmlID, Data: data}, nil
+}
+
+// parseIXML parses the iXML chunk c.
+func parseIXML(c Chunk) (IXML, error) {
+	var x IXML
+	// some writers pad the document with NUL bytes
+	doc := bytes.TrimRight(c.Data, "\x00")
+	err := xml.Unmarshal(doc, &x)
+	if err != nil {
+		return IXML{}, fmt.Errorf("parsing iXML: %s", err)
+	}
+	return x, nil
+}
+
+// IXML returns the metadata of the first iXML chunk (see Chunks), or
+// nil if there's none.
+func (d *Decoder) IXML() (*IXML, error) {
+	c, ok := d.chunk(isChunk(ixmlID))
+	if !ok {
+		return nil, nil
+	}
+	x, err := parseIXML(c)
+	if err != nil {
+		return nil, err
+	}
+	return &x, nil
+}
+
+// SetIXML sets the metadata written as an iXML chunk, replacing the
+// iXML chunks given to SetChunks. A nil iXML removes them.
+func (e *Encoder) SetIXML(x *IXML) {
+	e.ixml = &metadata{match: isChunk(ixmlID)}
+	if x != nil {
+		e.ixml.chunk = (*x).Chunk
+	}
+}
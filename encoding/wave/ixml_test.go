@@ -0,0 +1,102 @@
+package wave_test
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+
+	"github.com/NeowayLabs/signal/encoding/riff"
+	"github.com/NeowayLabs/signal/encoding/wave"
+)
+
+func TestIXMLRoundTrip(t *testing.T) {
+	expected := wave.IXML{
+		XMLName: xml.Name{Local: "BWFXML"},
+		Version: "1.61",
+		Project: "archive",
+		Scene:   "12",
+		Take:    "3",
+		Circled: "TRUE",
+		Note:    "call <4242> & co",
+		Speed: &wave.IXMLSpeed{
+			TimecodeRate:        "25/1",
+			TimecodeFlag:        "NDF",
+			FileSampleRate:      8000,
+			AudioBitDepth:       16,
+			TimestampLo:         396000000,
+			TimestampSampleRate: 8000,
+		},
+		TrackList: &wave.IXMLTrackList{
+			Count: 1,
+			Tracks: []wave.IXMLTrack{
+				{ChannelIndex: 1, InterleaveIndex: 1, Name: "agent"},
+			},
+		},
+	}
+
+	enc := wave.NewEncoder(wave.NewPCM(1, 8000, 16))
+	enc.SetIXML(&expected)
+	audio, err := enc.EncodeInt16([]int16{1, 2})
+	assertNoError(t, err)
+
+	d := decodeChunks(t, audio)
+	got, err := d.IXML()
+	assertNoError(t, err)
+	if got == nil || !reflect.DeepEqual(*got, expected) {
+		t.Fatalf("unexpected iXML: %#v", got)
+	}
+}
+
+func TestIXMLUnknownElements(t *testing.T) {
+	doc := `<?xml version="1.0" encoding="UTF-8"?>
+<BWFXML><IXML_VERSION>2.0</IXML_VERSION><PROJECT>p</PROJECT>` +
+		`<HISTORY><ORIGINAL_FILENAME>a.wav</ORIGINAL_FILENAME></HISTORY></BWFXML>` +
+		"\x00\x00"
+
+	enc := wave.NewEncoder(wave.NewPCM(1, 8000, 16))
+	enc.SetChunks([]wave.Chunk{{ID: riff.NewID("iXML"), Data: []byte(doc)}})
+	audio, err := enc.EncodeInt16([]int16{1})
+	assertNoError(t, err)
+
+	x, err := decodeChunks(t, audio).IXML()
+	assertNoError(t, err)
+	if x.Version != "2.0" || x.Project != "p" || len(x.Extra) != 1 ||
+		x.Extra[0].XMLName.Local != "HISTORY" {
+		t.Fatalf("unexpected iXML: %#v", x)
+	}
+
+	// the unknown elements are written back
+	enc.SetChunks(nil)
+	enc.SetIXML(x)
+	audio, err = enc.EncodeInt16([]int16{1})
+	assertNoError(t, err)
+
+	got, err := decodeChunks(t, audio).IXML()
+	assertNoError(t, err)
+	if !reflect.DeepEqual(got, x) {
+		t.Fatalf("unexpected iXML: %#v", got)
+	}
+
+	// removes the chunk
+	enc.SetChunks([]wave.Chunk{{ID: riff.NewID("iXML"), Data: []byte(doc)}})
+	enc.SetIXML(nil)
+	audio, err = enc.EncodeInt16([]int16{1})
+	assertNoError(t, err)
+
+	d := decodeChunks(t, audio)
+	got, err = d.IXML()
+	assertNoError(t, err)
+	if got != nil || len(d.Chunks()) != 0 {
+		t.Fatalf("iXML not removed: %#v", got)
+	}
+}
+
+func TestIXMLInvalid(t *testing.T) {
+	enc := wave.NewEncoder(wave.NewPCM(1, 8000, 16))
+	enc.SetChunks([]wave.Chunk{{ID: riff.NewID("iXML"), Data: []byte("<BWFXML><NOTE>")}})
+	audio, err := enc.EncodeInt16([]int16{1})
+	assertNoError(t, err)
+
+	_, err = decodeChunks(t, audio).IXML()
+	assertError(t, err)
+}
@@ -0,0 +1,245 @@
+package wave
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/NeowayLabs/signal/encoding/g711"
+)
+
+// header returns the header of the stream, decoding it first if it
+// wasn't yet.
+func (d *Decoder) header() (Header, error) {
+	if d.hdrDecoded {
+		return d.hdr, nil
+	}
+	return d.DecodeHeader()
+}
+
+// readBlock reads the raw bytes of up to nsamples samples of size
+// bytes each from the data chunk. The number of samples is rounded
+// down to whole frames (one sample for each channel).
+func (d *Decoder) readBlock(nsamples, size int) ([]byte, error) {
+	nchannels := int(d.hdr.NumChannels)
+	if nchannels == 0 {
+		nchannels = 1
+	}
+
+	nframes := nsamples / nchannels
+	if nframes == 0 {
+		return nil, io.ErrShortBuffer
+	}
+
+	if !d.unbounded && d.remaining == 0 {
+		err := d.endOfData()
+		if err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+
+	want := nframes * nchannels * size
+	if !d.unbounded && uint32(want) > d.remaining {
+		want = int(d.remaining)
+	}
+
+	if cap(d.buf) < want {
+		d.buf = make([]byte, want)
+	}
+
+	n, err := io.ReadFull(d.input, d.buf[:want])
+	d.consumed += uint32(n)
+	if !d.unbounded {
+		d.remaining -= uint32(n)
+	}
+
+	// discard incomplete trailing sample
+	raw := d.buf[:n-n%size]
+
+	switch err {
+	case nil:
+		if len(raw) == 0 {
+			// data chunk ends in the middle of a sample
+			return nil, io.ErrUnexpectedEOF
+		}
+		return raw, nil
+	case io.EOF, io.ErrUnexpectedEOF:
+		if !d.unbounded {
+			return raw, io.ErrUnexpectedEOF
+		}
+		if len(raw) == 0 {
+			return nil, io.EOF
+		}
+		d.remaining = 0
+		d.unbounded = false
+		return raw, nil
+	}
+	return raw, err
+}
+
+// checkFormat checks if the samples of the stream are stored with
+// the given format and bits per sample.
+func (d *Decoder) checkFormat(name string, format, bits uint16) error {
+	hdr, err := d.header()
+	if err != nil {
+		return err
+	}
+	if hdr.Format() != format {
+		return fmt.Errorf("reading %s: unsupported audio format: %d",
+			name, hdr.Format())
+	}
+	if hdr.BitsPerSample != bits {
+		return fmt.Errorf("reading %s: unsupported bits per sample: %d",
+			name, hdr.BitsPerSample)
+	}
+	return nil
+}
+
+// ReadUint8 reads up to len(buf) 8 bit unsigned PCM samples from the
+// data chunk, returning the number of samples read. It has the same
+// semantics of ReadInt16.
+func (d *Decoder) ReadUint8(buf []uint8) (int, error) {
+	err := d.checkFormat("uint8", FormatPCM, 8)
+	if err != nil {
+		return 0, err
+	}
+
+	raw, err := d.readBlock(len(buf), 1)
+	return copy(buf, raw), err
+}
+
+// ReadInt16 reads up to len(buf) 16 bit PCM samples from the data
+// chunk, returning the number of samples read. A-law and μ-law samples
+// are expanded to 16 bit linear PCM.
+// Only whole frames are read, then for multichannel audio, buf must
+// be big enough to hold at least one sample per channel.
+// At the end of the data chunk, ReadInt16 returns 0, io.EOF. If the
+// data chunk is truncated, it returns io.ErrUnexpectedEOF.
+func (d *Decoder) ReadInt16(buf []int16) (int, error) {
+	hdr, err := d.header()
+	if err != nil {
+		return 0, err
+	}
+
+	switch hdr.Format() {
+	case FormatALAW, FormatMULAW:
+		return d.readCompanded(buf)
+	}
+
+	err = d.checkFormat("int16", FormatPCM, 16)
+	if err != nil {
+		return 0, err
+	}
+
+	raw, err := d.readBlock(len(buf), 2)
+
+	n := len(raw) / 2
+	for i := 0; i < n; i++ {
+		buf[i] = int16(d.byteOrder.Uint16(raw[2*i:]))
+	}
+	return n, err
+}
+
+// readCompanded reads G.711 samples expanding them to linear PCM.
+func (d *Decoder) readCompanded(buf []int16) (int, error) {
+	format := d.hdr.Format()
+	err := d.checkFormat("int16", format, 8)
+	if err != nil {
+		return 0, err
+	}
+
+	expand := g711.DecodeALaw
+	if format == FormatMULAW {
+		expand = g711.DecodeMuLaw
+	}
+
+	raw, err := d.readBlock(len(buf), 1)
+	for i, v := range raw {
+		buf[i] = expand(v)
+	}
+	return len(raw), err
+}
+
+// ReadInt24 reads up to len(buf) packed 24 bit PCM samples from the
+// data chunk, returning the number of samples read. The samples are
+// sign extended to int32. It has the same semantics of ReadInt16.
+func (d *Decoder) ReadInt24(buf []int32) (int, error) {
+	err := d.checkFormat("int24", FormatPCM, 24)
+	if err != nil {
+		return 0, err
+	}
+
+	raw, err := d.readBlock(len(buf), 3)
+
+	n := len(raw) / 3
+	for i := 0; i < n; i++ {
+		buf[i] = int24(d.byteOrder, raw[3*i:])
+	}
+	return n, err
+}
+
+// ReadInt32 reads up to len(buf) 32 bit PCM samples from the data
+// chunk, returning the number of samples read. It has the same
+// semantics of ReadInt16.
+func (d *Decoder) ReadInt32(buf []int32) (int, error) {
+	err := d.checkFormat("int32", FormatPCM, 32)
+	if err != nil {
+		return 0, err
+	}
+
+	raw, err := d.readBlock(len(buf), 4)
+
+	n := len(raw) / 4
+	for i := 0; i < n; i++ {
+		buf[i] = int32(d.byteOrder.Uint32(raw[4*i:]))
+	}
+	return n, err
+}
+
+// ReadFloat32 reads up to len(buf) float samples from the data chunk,
+// returning the number of samples read. It has the same semantics of
+// ReadInt16.
+// The samples must be normalized in the [-1.0, 1.0] range. If any of
+// them isn't, all the samples read are returned with the error.
+func (d *Decoder) ReadFloat32(buf []float32) (int, error) {
+	const maxval float32 = 1.0
+	const minval float32 = -1.0
+
+	err := d.checkFormat("float32", FormatIEEEFloat, 32)
+	if err != nil {
+		return 0, err
+	}
+
+	raw, err := d.readBlock(len(buf), 4)
+
+	n := len(raw) / 4
+	var rangeErr error
+	for i := 0; i < n; i++ {
+		sample := math.Float32frombits(d.byteOrder.Uint32(raw[4*i:]))
+		if (sample < minval || sample > maxval) && rangeErr == nil {
+			rangeErr = fmt.Errorf(
+				"sample[%f] is outside the valid value range for a PCM float",
+				sample,
+			)
+		}
+		buf[i] = sample
+	}
+	if rangeErr != nil {
+		return n, rangeErr
+	}
+	return n, err
+}
+
+// int24 decodes the 3 bytes of b as a signed 24 bit integer.
+func int24(order binary.ByteOrder, b []byte) int32 {
+	var v uint32
+	if order == binary.BigEndian {
+		v = uint32(b[0])<<16 | uint32(b[1])<<8 | uint32(b[2])
+	} else {
+		v = uint32(b[2])<<16 | uint32(b[1])<<8 | uint32(b[0])
+	}
+	// sign extension
+	return int32(v<<8) >> 8
+}
@@ -0,0 +1,125 @@
+package wave_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/NeowayLabs/signal/encoding/wave"
+)
+
+func readAllInt16(t *testing.T, d *wave.Decoder, blocksz int) []int16 {
+	t.Helper()
+
+	var samples []int16
+	buf := make([]int16, blocksz)
+	for {
+		n, err := d.ReadInt16(buf)
+		samples = append(samples, buf[:n]...)
+		if err == io.EOF {
+			return samples
+		}
+		assertNoError(t, err)
+	}
+}
+
+func TestReadInt16Blocks(t *testing.T) {
+	f, err := os.Open("testdata/audios/sint16le.wav")
+	assertNoError(t, err)
+	defer f.Close()
+
+	expected := []int16{}
+	_, err = wave.NewDecoder(f).DecodeInt16(&expected)
+	assertNoError(t, err)
+
+	for _, blocksz := range []int{1, 7, 1000, 100000} {
+		_, err = f.Seek(0, io.SeekStart)
+		assertNoError(t, err)
+
+		d := wave.NewDecoder(f)
+		_, err = d.DecodeHeader()
+		assertNoError(t, err)
+
+		got := readAllInt16(t, d, blocksz)
+		if !reflect.DeepEqual(got, expected) {
+			t.Fatalf("block size %d: samples differs", blocksz)
+		}
+	}
+}
+
+func TestReadFloat32StopEarly(t *testing.T) {
+	f, err := os.Open("testdata/audios/float32le.wav")
+	assertNoError(t, err)
+	defer f.Close()
+
+	d := wave.NewDecoder(f)
+	buf := make([]float32, 16)
+
+	n, err := d.ReadFloat32(buf)
+	assertNoError(t, err)
+	if n != len(buf) {
+		t.Fatalf("expected %d samples, got %d", len(buf), n)
+	}
+}
+
+func TestReadFloat32OutOfRange(t *testing.T) {
+	enc := wave.NewEncoder(wave.NewIEEEFloat(1, 8000, 32))
+	audio, err := enc.EncodeFloat32([]float32{0.5, 1.5, -0.5})
+	assertNoError(t, err)
+
+	d := wave.NewDecoder(bytes.NewReader(audio))
+	buf := make([]float32, 3)
+	n, err := d.ReadFloat32(buf)
+	assertError(t, err)
+	if n != 3 || buf[0] != 0.5 || buf[2] != -0.5 {
+		t.Fatalf("unexpected samples: %d %v", n, buf)
+	}
+}
+
+func TestReadTruncatedData(t *testing.T) {
+	enc := wave.NewEncoder(wave.NewPCM(1, 8000, 16))
+	audio, err := enc.EncodeInt16([]int16{1, 2, 3, 4, 5})
+	assertNoError(t, err)
+
+	// cut the last sample and a half
+	d := wave.NewDecoder(bytes.NewReader(audio[:len(audio)-3]))
+	buf := make([]int16, 10)
+
+	n, err := d.ReadInt16(buf)
+	if err != io.ErrUnexpectedEOF {
+		t.Fatalf("expected unexpected EOF, got %v", err)
+	}
+	if !reflect.DeepEqual(buf[:n], []int16{1, 2, 3}) {
+		t.Fatalf("unexpected samples: %v", buf[:n])
+	}
+}
+
+func TestReadUnknownSize(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc, err := wave.NewStreamEncoder(buf, wave.NewPCM(2, 8000, 16))
+	assertNoError(t, err)
+
+	expected := []int16{1, -1, 2, -2, 3, -3}
+	assertNoError(t, enc.WriteInt16(expected))
+	assertNoError(t, enc.Close())
+
+	d := wave.NewDecoder(buf)
+	got := readAllInt16(t, d, 4)
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("samples differs: %v != %v", got, expected)
+	}
+}
+
+func TestReadShortBuffer(t *testing.T) {
+	enc := wave.NewEncoder(wave.NewPCM(2, 8000, 16))
+	audio, err := enc.EncodeInt16([]int16{1, 2})
+	assertNoError(t, err)
+
+	d := wave.NewDecoder(bytes.NewReader(audio))
+	_, err = d.ReadInt16(make([]int16, 1))
+	if err != io.ErrShortBuffer {
+		t.Fatalf("expected short buffer, got %v", err)
+	}
+}
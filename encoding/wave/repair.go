@@ -0,0 +1,265 @@
+package wave
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+
+	"github.com/NeowayLabs/signal/encoding/riff"
+)
+
+// RepairReport describes the changes made by Repair.
+type RepairReport struct {
+	Header  Header   // header of the repaired file
+	Changes []string // description of each change, empty for clean files
+}
+
+// Repair reads a truncated or corrupted WAVE file from r and writes a
+// clean file into w: the fields derived from the number of channels,
+// the bits per sample and the sample rate are recomputed; the RIFF and
+// data chunk sizes are recomputed from the real payload; a partial
+// trailing frame is truncated; and the garbage bytes between the
+// chunks, the truncated chunks and the duplicated fmt and data chunks
+// are dropped. The other well-formed chunks are kept, written between
+// the fmt and the data chunks.
+// The files without a RIFF/WAVE header, fmt chunk or data chunk, or
+// with an unknown audio format, can't be repaired.
+func Repair(r io.Reader, w io.Writer) (RepairReport, error) {
+	var report RepairReport
+	change := func(format string, args ...interface{}) {
+		report.Changes = append(report.Changes, fmt.Sprintf(format, args...))
+	}
+
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		return report, fmt.Errorf("reading input: %s", err)
+	}
+	if len(raw) < 12 || string(raw[0:4]) != "RIFF" || string(raw[8:12]) != "WAVE" {
+		return report, fmt.Errorf("not a RIFF/WAVE file")
+	}
+
+	var (
+		fmtChunk []byte // fmt chunk with its size field
+		data     []byte
+		hasData  bool
+		chunks   []Chunk
+	)
+
+	pos := 12
+	for pos+8 <= len(raw) {
+		var id riff.ID
+		copy(id[:], raw[pos:pos+4])
+		if !id.Valid() {
+			next := nextKnownChunk(raw, pos+1)
+			if next < 0 {
+				change("dropped %d garbage bytes at offset %d", len(raw)-pos, pos)
+				pos = len(raw)
+				break
+			}
+			change("dropped %d garbage bytes at offset %d", next-pos, pos)
+			pos = next
+			continue
+		}
+
+		size := int64(binary.LittleEndian.Uint32(raw[pos+4 : pos+8]))
+		start := pos + 8
+		avail := int64(len(raw) - start)
+
+		switch {
+		case id.String() == "fmt " && fmtChunk == nil:
+			if size < 16 || avail < 16 {
+				return report, fmt.Errorf("truncated fmt chunk: size[%d], available[%d]",
+					size, avail)
+			}
+			if size > avail {
+				size = avail
+			}
+			fmtChunk = raw[pos+4 : int64(start)+size]
+		case id.String() == "data" && !hasData:
+			hasData = true
+			switch {
+			case size == 0 && validChunks(raw, start):
+				// empty data chunk followed by other chunks
+			case size == 0 || size > avail:
+				// unknown size (eg. of an unfinished file) or truncated
+				change("data chunk size: declared %d, found %d bytes", size, avail)
+				size = avail
+			}
+			data = raw[start : int64(start)+size]
+		case id.String() == "fmt " || id.String() == "data":
+			if size > avail {
+				size = avail
+			}
+			change("dropped duplicate %q chunk (%d bytes) at offset %d", id, size, pos)
+		case size > avail:
+			if next := nextKnownChunk(raw, start); next >= 0 {
+				change("dropped chunk %q with invalid size %d at offset %d", id, size, pos)
+				pos = next
+				continue
+			}
+			change("dropped truncated chunk %q (%d bytes of %d) at offset %d",
+				id, avail, size, pos)
+			size = avail
+		default:
+			chunks = append(chunks, Chunk{ID: id, Data: raw[start : int64(start)+size]})
+		}
+
+		pos = start + int(size+size&1)
+	}
+	if pos < len(raw) {
+		change("dropped %d trailing bytes", len(raw)-pos)
+	}
+
+	if fmtChunk == nil {
+		return report, fmt.Errorf("fmt chunk not found")
+	}
+	if !hasData {
+		return report, fmt.Errorf("data chunk not found")
+	}
+
+	hdr, err := repairFmt(fmtChunk, change)
+	if err != nil {
+		return report, err
+	}
+
+	if partial := len(data) % int(hdr.BytesPerBloc); partial != 0 {
+		change("dropped %d bytes of a partial trailing frame", partial)
+		data = data[:len(data)-partial]
+	}
+
+	if hdr.needsExtensible() {
+		change("AudioFormat: %d -> %d (WAVE_FORMAT_EXTENSIBLE)",
+			hdr.AudioFormat, FormatExtensible)
+		hdr = hdr.extensible()
+	}
+
+	// the sizes are known, so the header is written with them even
+	// for seekable outputs
+	s := &StreamEncoder{
+		hdr:       hdr,
+		output:    w,
+		chunks:    chunks,
+		byteOrder: binary.LittleEndian,
+	}
+
+	datasz := uint32(len(data))
+	s.hdr.RiffHeader = waveRiff()
+	s.hdr.RiffHeader.ChunkSize = s.riffChunkSize(datasz)
+	s.hdr.DataBlockSize = datasz
+	if old := binary.LittleEndian.Uint32(raw[4:8]); old != s.hdr.RiffHeader.ChunkSize {
+		change("RIFF chunk size: %d -> %d", old, s.hdr.RiffHeader.ChunkSize)
+	}
+	report.Header = s.hdr
+
+	err = s.writeHeader(datasz)
+	if err != nil {
+		return report, fmt.Errorf("writing header: %s", err)
+	}
+	err = s.write(data, len(data))
+	if err != nil {
+		return report, fmt.Errorf("writing data: %s", err)
+	}
+	return report, s.Close()
+}
+
+// repairFmt parses the fmt chunk (starting at its size field),
+// recomputing the fields derived from the others.
+func repairFmt(chunk []byte, change func(string, ...interface{})) (Header, error) {
+	var hdr Header
+
+	err := binary.Read(bytes.NewReader(chunk), binary.LittleEndian, &hdr.RiffChunkFmt)
+	if err != nil {
+		return hdr, fmt.Errorf("parsing fmt chunk: %s", err)
+	}
+	size := len(chunk) - 4
+
+	switch {
+	case hdr.AudioFormat == FormatExtensible:
+		if size < 16+2+fmtExtensionSize {
+			return hdr, fmt.Errorf("truncated extensible fmt chunk: %d bytes", size)
+		}
+		err = binary.Read(bytes.NewReader(chunk[4+18:]), binary.LittleEndian, &hdr.Extension)
+		if err != nil {
+			return hdr, fmt.Errorf("parsing fmt extension: %s", err)
+		}
+		if _, ok := hdr.Extension.SubFormat.Format(); !ok {
+			return hdr, fmt.Errorf("unsupported audio format: subformat[%s]",
+				hdr.Extension.SubFormat)
+		}
+		if hdr.LengthOfHeader != 16+2+fmtExtensionSize {
+			change("fmt chunk size: %d -> %d", hdr.LengthOfHeader, 16+2+fmtExtensionSize)
+			hdr.LengthOfHeader = 16 + 2 + fmtExtensionSize
+		}
+		if hdr.Extension.ValidBitsPerSample > hdr.BitsPerSample {
+			change("ValidBitsPerSample: %d -> %d",
+				hdr.Extension.ValidBitsPerSample, hdr.BitsPerSample)
+			hdr.Extension.ValidBitsPerSample = hdr.BitsPerSample
+		}
+	case isValidWavFormat(hdr.AudioFormat):
+		if hdr.LengthOfHeader != 16 && hdr.LengthOfHeader != 18 {
+			change("fmt chunk size: %d -> 16", hdr.LengthOfHeader)
+			hdr.LengthOfHeader = 16
+		}
+	default:
+		return hdr, fmt.Errorf("unsupported audio format: %d", hdr.AudioFormat)
+	}
+
+	if hdr.NumChannels == 0 || hdr.SampleRate == 0 || hdr.BitsPerSample == 0 {
+		return hdr, fmt.Errorf("invalid fmt chunk: channels[%d], sample rate[%d], bits[%d]",
+			hdr.NumChannels, hdr.SampleRate, hdr.BitsPerSample)
+	}
+
+	blocksz, err := blockSize(int(hdr.NumChannels), int(hdr.BitsPerSample))
+	if err != nil {
+		return hdr, fmt.Errorf("invalid fmt chunk: %s", err)
+	}
+	if int(hdr.BytesPerBloc) != blocksz {
+		change("BytesPerBloc: %d -> %d", hdr.BytesPerBloc, blocksz)
+		hdr.BytesPerBloc = uint16(blocksz)
+	}
+	bytesPerSec := uint64(blocksz) * uint64(hdr.SampleRate)
+	if bytesPerSec > math.MaxUint32 {
+		return hdr, fmt.Errorf("invalid fmt chunk: byte rate overflows: %d", bytesPerSec)
+	}
+	if hdr.BytesPerSec != uint32(bytesPerSec) {
+		change("BytesPerSec: %d -> %d", hdr.BytesPerSec, bytesPerSec)
+		hdr.BytesPerSec = uint32(bytesPerSec)
+	}
+	return hdr, nil
+}
+
+// validChunks tells if the bytes from the offset pos until the end of
+// raw are a sequence of well-formed chunks (the last one may miss the
+// pad byte).
+func validChunks(raw []byte, pos int) bool {
+	for pos < len(raw) {
+		if pos+8 > len(raw) {
+			return false
+		}
+		var id riff.ID
+		copy(id[:], raw[pos:pos+4])
+		size := int64(binary.LittleEndian.Uint32(raw[pos+4 : pos+8]))
+		end := int64(pos+8) + size
+		if !id.Valid() || end > int64(len(raw)) {
+			return false
+		}
+		pos = int(end + size&1)
+	}
+	return true
+}
+
+// nextKnownChunk returns the offset of the next fmt or data chunk ID
+// from the offset from, or -1 if there's none.
+func nextKnownChunk(raw []byte, from int) int {
+	next := -1
+	for _, id := range []string{"fmt ", "data"} {
+		i := bytes.Index(raw[from:], []byte(id))
+		if i >= 0 && (next < 0 || from+i < next) {
+			next = from + i
+		}
+	}
+	return next
+}
@@ -0,0 +1,276 @@
+package wave_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/NeowayLabs/signal/encoding/wave"
+)
+
+// decodeStrict decodes the 16 bit samples of audio, failing on any
+// header violation.
+func decodeStrict(t *testing.T, audio []byte) (wave.Header, []int16) {
+	t.Helper()
+
+	d := wave.NewDecoder(bytes.NewReader(audio))
+	d.Strict()
+
+	var samples []int16
+	hdr, err := d.DecodeInt16(&samples)
+	assertNoError(t, err)
+	return hdr, samples
+}
+
+func hasChange(report wave.RepairReport, prefix string) bool {
+	for _, c := range report.Changes {
+		if strings.HasPrefix(c, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRepairCleanFile(t *testing.T) {
+	audio, err := wave.NewEncoder(wave.NewPCM(2, 8000, 16)).EncodeInt16([]int16{1, 2, 3, 4})
+	assertNoError(t, err)
+
+	var out bytes.Buffer
+	report, err := wave.Repair(bytes.NewReader(audio), &out)
+	assertNoError(t, err)
+
+	if len(report.Changes) != 0 {
+		t.Fatalf("unexpected changes: %v", report.Changes)
+	}
+	assertBytesEqual(t, audio, out.Bytes())
+}
+
+func TestRepairTestdata(t *testing.T) {
+	for _, fname := range []string{"r.wav", "79crrn.wav", "79crrn.cleansed.wav", "dafuq.wav"} {
+		audio, err := ioutil.ReadFile("testdata/" + fname)
+		assertNoError(t, err)
+
+		var out bytes.Buffer
+		report, err := wave.Repair(bytes.NewReader(audio), &out)
+		assertNoError(t, err)
+
+		_, expected := decodeStrict(t, audio)
+		hdr, got := decodeStrict(t, out.Bytes())
+		if len(got) != len(expected) {
+			t.Fatalf("%s: samples differ: %d != %d", fname, len(got), len(expected))
+		}
+		for i := range got {
+			if got[i] != expected[i] {
+				t.Fatalf("%s: sample[%d] differs: %d != %d", fname, i, got[i], expected[i])
+			}
+		}
+		if hdr.RiffHeader.ChunkSize != uint32(out.Len()-8) {
+			t.Fatalf("%s: unexpected RIFF size: %d", fname, hdr.RiffHeader.ChunkSize)
+		}
+		if hdr != report.Header {
+			t.Fatalf("%s: reported header differs: %#v", fname, report.Header)
+		}
+	}
+}
+
+func TestRepairKeepsChunks(t *testing.T) {
+	audio, err := ioutil.ReadFile("testdata/r.wav")
+	assertNoError(t, err)
+
+	var out bytes.Buffer
+	report, err := wave.Repair(bytes.NewReader(audio), &out)
+	assertNoError(t, err)
+
+	if len(report.Changes) != 0 {
+		t.Fatalf("unexpected changes: %v", report.Changes)
+	}
+	if !bytes.Equal(out.Bytes(), audio) {
+		t.Fatal("repaired file differs from the well-formed one")
+	}
+}
+
+func TestRepairDropsTruncatedChunk(t *testing.T) {
+	audio, err := wave.NewEncoder(wave.NewPCM(1, 8000, 16)).EncodeInt16([]int16{1, 2, 3, 4})
+	assertNoError(t, err)
+
+	// LIST chunk declaring 100 bytes at the end of the file
+	truncated := append([]byte{}, audio...)
+	truncated = append(truncated, 'L', 'I', 'S', 'T', 100, 0, 0, 0, 'I', 'N', 'F', 'O')
+
+	var out bytes.Buffer
+	report, err := wave.Repair(bytes.NewReader(truncated), &out)
+	assertNoError(t, err)
+
+	if !hasChange(report, `dropped truncated chunk "LIST" (4 bytes of 100)`) {
+		t.Fatalf("unexpected changes: %v", report.Changes)
+	}
+	if !bytes.Equal(out.Bytes(), audio) {
+		t.Fatal("repaired file differs from the original")
+	}
+}
+
+func TestRepairTruncated(t *testing.T) {
+	audio, err := wave.NewEncoder(wave.NewPCM(2, 8000, 16)).EncodeInt16([]int16{1, 2, 3, 4, 5, 6})
+	assertNoError(t, err)
+
+	// cuts the file in the middle of the last frame
+	truncated := audio[:len(audio)-3]
+
+	var out bytes.Buffer
+	report, err := wave.Repair(bytes.NewReader(truncated), &out)
+	assertNoError(t, err)
+
+	for _, change := range []string{
+		"data chunk size: declared 12, found 9 bytes",
+		"dropped 1 bytes of a partial trailing frame",
+		"RIFF chunk size: 48 -> 44",
+	} {
+		if !hasChange(report, change) {
+			t.Fatalf("change %q not found: %v", change, report.Changes)
+		}
+	}
+
+	_, samples := decodeStrict(t, out.Bytes())
+	if len(samples) != 4 {
+		t.Fatalf("unexpected samples: %v", samples)
+	}
+}
+
+func TestRepairCorruptedHeader(t *testing.T) {
+	audio, err := wave.NewEncoder(wave.NewPCM(1, 8000, 16)).EncodeInt16([]int16{1, 2, 3, 4})
+	assertNoError(t, err)
+
+	// wrong byte rate and block align, and garbage before data
+	binary.LittleEndian.PutUint32(audio[28:], 1)
+	binary.LittleEndian.PutUint16(audio[32:], 7)
+	corrupted := append([]byte{}, audio[:36]...)
+	corrupted = append(corrupted, 0xFF, 0x00, 0x13)
+	corrupted = append(corrupted, audio[36:]...)
+
+	var out bytes.Buffer
+	report, err := wave.Repair(bytes.NewReader(corrupted), &out)
+	assertNoError(t, err)
+
+	for _, change := range []string{
+		"BytesPerSec: 1 -> 16000",
+		"BytesPerBloc: 7 -> 2",
+		"dropped 3 garbage bytes at offset 36",
+	} {
+		if !hasChange(report, change) {
+			t.Fatalf("change %q not found: %v", change, report.Changes)
+		}
+	}
+
+	_, samples := decodeStrict(t, out.Bytes())
+	if len(samples) != 4 || samples[3] != 4 {
+		t.Fatalf("unexpected samples: %v", samples)
+	}
+}
+
+func TestRepairUnrepairable(t *testing.T) {
+	audio, err := wave.NewEncoder(wave.NewPCM(1, 8000, 16)).EncodeInt16([]int16{1, 2})
+	assertNoError(t, err)
+
+	for name, corrupted := range map[string][]byte{
+		"not riff":   append([]byte("RIFX"), audio[4:]...),
+		"no data":    audio[:36],
+		"short fmt":  audio[:24],
+		"no fmt":     append(append([]byte{}, audio[:12]...), audio[36:]...),
+		"no samples": audio[:2],
+	} {
+		_, err := wave.Repair(bytes.NewReader(corrupted), ioutil.Discard)
+		if err == nil {
+			t.Fatalf("%s: must fail", name)
+		}
+	}
+}
+
+func TestRepairBlockSizeOverflow(t *testing.T) {
+	audio, err := wave.NewEncoder(wave.NewIEEEFloat(1, 8000, 32)).EncodeFloat32([]float32{0, 0})
+	assertNoError(t, err)
+
+	// 16384 channels of 32 bits overflows BytesPerBloc
+	binary.LittleEndian.PutUint16(audio[22:], 16384)
+	_, err = wave.Repair(bytes.NewReader(audio), ioutil.Discard)
+	assertError(t, err)
+}
+
+func TestRepairInvalidChunkSize(t *testing.T) {
+	audio, err := wave.NewEncoder(wave.NewPCM(1, 8000, 16)).EncodeInt16([]int16{1, 2})
+	assertNoError(t, err)
+
+	// chunk with a printable ID and a huge size before data
+	corrupted := append([]byte{}, audio[:36]...)
+	corrupted = append(corrupted, 'j', 'u', 'n', 'k', 0xF0, 0xFF, 0xFF, 0x7F, 1, 2)
+	corrupted = append(corrupted, audio[36:]...)
+
+	var out bytes.Buffer
+	report, err := wave.Repair(bytes.NewReader(corrupted), &out)
+	assertNoError(t, err)
+
+	if !hasChange(report, `dropped chunk "junk" with invalid size 2147483632 at offset 36`) {
+		t.Fatalf("unexpected changes: %v", report.Changes)
+	}
+	assertBytesEqual(t, audio, out.Bytes())
+}
+
+func TestRepairEmptyData(t *testing.T) {
+	audio, err := wave.NewEncoder(wave.NewPCM(1, 8000, 16)).EncodeInt16(nil)
+	assertNoError(t, err)
+
+	// empty data chunk followed by a chunk, that isn't audio
+	withChunk := append([]byte{}, audio...)
+	withChunk = append(withChunk, 'n', 'o', 't', 'e', 2, 0, 0, 0, 1, 2)
+
+	var out bytes.Buffer
+	report, err := wave.Repair(bytes.NewReader(withChunk), &out)
+	assertNoError(t, err)
+
+	hdr, samples := decodeStrict(t, out.Bytes())
+	if hdr.DataBlockSize != 0 || len(samples) != 0 || hasChange(report, "data chunk size") {
+		t.Fatalf("unexpected samples: %v, changes: %v", samples, report.Changes)
+	}
+
+	// data chunk of unknown size followed by the samples
+	unfinished := append([]byte{}, audio...)
+	unfinished = append(unfinished, 1, 0, 2, 0)
+
+	out.Reset()
+	report, err = wave.Repair(bytes.NewReader(unfinished), &out)
+	assertNoError(t, err)
+
+	_, samples = decodeStrict(t, out.Bytes())
+	if len(samples) != 2 || !hasChange(report, "data chunk size: declared 0, found 4 bytes") {
+		t.Fatalf("unexpected samples: %v, changes: %v", samples, report.Changes)
+	}
+}
+
+func TestRepairDuplicateChunks(t *testing.T) {
+	audio, err := wave.NewEncoder(wave.NewPCM(1, 8000, 16)).EncodeInt16([]int16{1, 2})
+	assertNoError(t, err)
+
+	// second fmt and data chunks with garbage at the end
+	dup := append([]byte{}, audio...)
+	dup = append(dup, audio[12:36]...)
+	dup = append(dup, 'd', 'a', 't', 'a', 2, 0, 0, 0, 0x09, 0x09)
+
+	var out bytes.Buffer
+	report, err := wave.Repair(bytes.NewReader(dup), &out)
+	assertNoError(t, err)
+
+	for _, change := range []string{
+		`dropped duplicate "fmt " chunk (16 bytes) at offset 48`,
+		`dropped duplicate "data" chunk (2 bytes) at offset 72`,
+	} {
+		if !hasChange(report, change) {
+			t.Fatalf("change %q not found: %v", change, report.Changes)
+		}
+	}
+
+	_, samples := decodeStrict(t, out.Bytes())
+	if len(samples) != 2 || samples[0] != 1 || samples[1] != 2 {
+		t.Fatalf("unexpected samples: %v", samples)
+	}
+}
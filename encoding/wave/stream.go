@@ -0,0 +1,365 @@
+package wave
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/NeowayLabs/signal/encoding/g711"
+	"github.com/NeowayLabs/signal/encoding/riff"
+)
+
+// streamingSize is the chunk size used when the final size of the
+// stream isn't known at the time the header is written.
+const streamingSize = 0xFFFFFFFF
+
+type (
+	// StreamEncoder writes a WAVE file incrementally into an output
+	// stream, without holding the samples in memory.
+	//
+	// A provisional header is written when the encoder is created and,
+	// if the output is seekable, the RIFF and data chunk sizes are
+	// patched on Close. For non-seekable outputs (pipes, sockets, etc)
+	// the header carries the DataBlockSize declared in the header given
+	// to NewStreamEncoder or, if it's zero, the 0xFFFFFFFF streaming
+	// size that most readers interpret as "read until the end".
+	StreamEncoder struct {
+		hdr       Header
+		output    io.Writer
+		seeker    io.Seeker        // nil if output isn't seekable
+		start     int64            // offset of RIFF header in output
+		dataszOff int64            // offset of data chunk size from start
+		chunks    []Chunk          // chunks written before the data chunk
+		sized     bool             // hdr.DataBlockSize is the exact data size
+		written   uint32           // bytes of samples written so far
+		byteOrder binary.ByteOrder // encoder's byte order for data samples
+		closed    bool
+
+		quantizer
+	}
+)
+
+// ErrClosed is returned when writing into a closed StreamEncoder.
+var ErrClosed = errors.New("wave: write to closed stream encoder")
+
+// NewStreamEncoder creates a new stream encoder writing a WAVE file
+// with header hdr into w. The header is written immediately, followed
+// by the chunks (eg. the ones of a decoded file, see Decoder.Chunks),
+// that are written between the fmt and the data chunks.
+// PCM headers with more than 16 bits per sample and headers with more
+// than 2 channels are written as WAVE_FORMAT_EXTENSIBLE.
+func NewStreamEncoder(w io.Writer, hdr Header, chunks ...Chunk) (*StreamEncoder, error) {
+	return newStreamEncoder(w, hdr, hdr.DataBlockSize != 0, chunks)
+}
+
+// newStreamEncoder creates a new stream encoder. If sized is true, the
+// DataBlockSize of hdr is the exact size of the data, even if zero,
+// otherwise the size is unknown.
+func newStreamEncoder(w io.Writer, hdr Header, sized bool, chunks []Chunk) (*StreamEncoder, error) {
+	if hdr.needsExtensible() {
+		hdr = hdr.extensible()
+	}
+
+	s := &StreamEncoder{
+		hdr:       hdr,
+		output:    w,
+		chunks:    chunks,
+		sized:     sized,
+		byteOrder: binary.LittleEndian,
+	}
+
+	if ws, ok := w.(io.WriteSeeker); ok {
+		// os.File implements io.Seeker even for pipes, where
+		// seeking fails.
+		start, err := ws.Seek(0, io.SeekCurrent)
+		if err == nil {
+			s.seeker = ws
+			s.start = start
+		}
+	}
+
+	datasz := uint32(streamingSize)
+	if sized {
+		datasz = hdr.DataBlockSize
+	}
+
+	err := s.writeHeader(datasz)
+	if err != nil {
+		return nil, fmt.Errorf("writing header: %s", err)
+	}
+	return s, nil
+}
+
+// riffChunkSize returns the size of the RIFF chunk with datasz bytes
+// of samples.
+func (s *StreamEncoder) riffChunkSize(datasz uint32) uint32 {
+	if datasz == streamingSize {
+		return streamingSize
+	}
+
+	// everything after the RIFF chunk header
+	size := uint64(s.dataOffset()-riff.HeaderSize) + uint64(datasz) + uint64(datasz&1)
+	if size > math.MaxUint32 {
+		return streamingSize
+	}
+	return uint32(size)
+}
+
+func (s *StreamEncoder) writeHeader(datasz uint32) error {
+	lewrite := func(d interface{}) error {
+		return binary.Write(s.output, binary.LittleEndian, d)
+	}
+
+	err := lewrite(s.hdr.RiffHeader.Ident)
+	if err != nil {
+		return err
+	}
+
+	err = lewrite(s.riffChunkSize(datasz))
+	if err != nil {
+		return err
+	}
+
+	err = lewrite(s.hdr.RiffHeader.FileType)
+	if err != nil {
+		return err
+	}
+
+	err = lewrite([4]byte{'f', 'm', 't', ' '})
+	if err != nil {
+		return err
+	}
+
+	err = lewrite(s.hdr.RiffChunkFmt)
+	if err != nil {
+		return err
+	}
+
+	err = s.writeFmtExtension()
+	if err != nil {
+		return err
+	}
+
+	chunks := riff.NewWriter(s.output)
+	for _, c := range s.chunks {
+		err = chunks.WriteChunk(c.ID, c.Data)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = lewrite([4]byte{'d', 'a', 't', 'a'})
+	if err != nil {
+		return err
+	}
+
+	s.dataszOff = s.dataOffset() - 4
+	return lewrite(datasz)
+}
+
+// dataOffset returns the offset of the samples from the start of the
+// RIFF header.
+func (s *StreamEncoder) dataOffset() int64 {
+	// RIFF header, fmt chunk, other chunks and data chunk header
+	offset := 12 + riff.PaddedSize(s.hdr.LengthOfHeader)
+	for _, c := range s.chunks {
+		offset += riff.PaddedSize(uint32(len(c.Data)))
+	}
+	return offset + riff.HeaderSize
+}
+
+// writeFmtExtension writes the extra params of the fmt chunk.
+func (s *StreamEncoder) writeFmtExtension() error {
+	lewrite := func(d interface{}) error {
+		return binary.Write(s.output, binary.LittleEndian, d)
+	}
+
+	switch {
+	case s.hdr.AudioFormat == FormatExtensible:
+		if s.hdr.LengthOfHeader != 16+2+fmtExtensionSize {
+			return fmt.Errorf("invalid extensible fmt chunk size: %d",
+				s.hdr.LengthOfHeader)
+		}
+		err := lewrite(uint16(fmtExtensionSize))
+		if err != nil {
+			return err
+		}
+		return lewrite(s.hdr.Extension)
+	case s.hdr.LengthOfHeader == 18:
+		return lewrite(uint16(0))
+	case s.hdr.LengthOfHeader != 16:
+		return fmt.Errorf("unsupported fmt chunk size: %d", s.hdr.LengthOfHeader)
+	}
+	return nil
+}
+
+// checkFormat checks if the stream stores samples with the given
+// format and bits per sample.
+func (s *StreamEncoder) checkFormat(name string, format, bits uint16) error {
+	if s.hdr.Format() != format {
+		return fmt.Errorf("writing %s: unsupported audio format: %d",
+			name, s.hdr.Format())
+	}
+	if s.hdr.BitsPerSample != bits {
+		return fmt.Errorf("writing %s: unsupported bits per sample: %d",
+			name, s.hdr.BitsPerSample)
+	}
+	return nil
+}
+
+func (s *StreamEncoder) write(data interface{}, size int) error {
+	if s.closed {
+		return ErrClosed
+	}
+
+	if uint64(s.written)+uint64(size) >= streamingSize {
+		return fmt.Errorf("data chunk exceeds the maximum size: written[%d] + %d",
+			s.written, size)
+	}
+
+	err := binary.Write(s.output, s.byteOrder, data)
+	if err != nil {
+		return err
+	}
+	s.written += uint32(size)
+	return nil
+}
+
+// WriteUint8 writes a block of 8 bit unsigned samples.
+func (s *StreamEncoder) WriteUint8(data []uint8) error {
+	err := s.checkFormat("uint8", FormatPCM, 8)
+	if err != nil {
+		return err
+	}
+	return s.write(data, len(data))
+}
+
+// WriteInt16 writes a block of 16 bit samples. If the stream stores
+// A-law or μ-law data, the samples are compressed.
+func (s *StreamEncoder) WriteInt16(data []int16) error {
+	switch s.hdr.Format() {
+	case FormatALAW:
+		return s.writeCompanded(data, g711.CompressALaw)
+	case FormatMULAW:
+		return s.writeCompanded(data, g711.CompressMuLaw)
+	}
+
+	err := s.checkFormat("int16", FormatPCM, 16)
+	if err != nil {
+		return err
+	}
+	return s.write(data, 2*len(data))
+}
+
+func (s *StreamEncoder) writeCompanded(data []int16, compress func([]int16) []uint8) error {
+	err := s.checkFormat("int16", s.hdr.Format(), 8)
+	if err != nil {
+		return err
+	}
+	return s.write(compress(data), len(data))
+}
+
+// WriteInt24 writes a block of samples as packed 24 bit integers.
+// The samples must be in the [-8388608, 8388607] range, the upper
+// byte of each int32 is discarded.
+func (s *StreamEncoder) WriteInt24(data []int32) error {
+	err := s.checkFormat("int24", FormatPCM, 24)
+	if err != nil {
+		return err
+	}
+
+	raw := make([]byte, 3*len(data))
+	for i, v := range data {
+		putInt24(s.byteOrder, raw[3*i:], v)
+	}
+	return s.write(raw, len(raw))
+}
+
+// WriteInt32 writes a block of 32 bit samples.
+func (s *StreamEncoder) WriteInt32(data []int32) error {
+	err := s.checkFormat("int32", FormatPCM, 32)
+	if err != nil {
+		return err
+	}
+	return s.write(data, 4*len(data))
+}
+
+// WriteFloat32 writes a block of float samples.
+func (s *StreamEncoder) WriteFloat32(data []float32) error {
+	err := s.checkFormat("float32", FormatIEEEFloat, 32)
+	if err != nil {
+		return err
+	}
+	return s.write(data, 4*len(data))
+}
+
+// Close finishes the WAVE stream, writing the pad byte of the data
+// chunk and, if the output is seekable, patching the header with the
+// real sizes. If the output isn't seekable and the header declared a
+// DataBlockSize, an error is returned if a different amount of
+// sample data was written.
+// Close doesn't close the underlying writer.
+func (s *StreamEncoder) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	// RIFF chunks are word aligned
+	if s.written&1 == 1 {
+		_, err := s.output.Write([]byte{0})
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.seeker == nil {
+		declared := s.hdr.DataBlockSize
+		if s.sized && declared != s.written {
+			return fmt.Errorf("data size differs from declared: declared[%d], written[%d]",
+				declared, s.written)
+		}
+		return nil
+	}
+
+	return s.patchSizes()
+}
+
+func (s *StreamEncoder) patchSizes() error {
+	end, err := s.seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	patch := func(offset int64, value uint32) error {
+		_, err := s.seeker.Seek(s.start+offset, io.SeekStart)
+		if err != nil {
+			return err
+		}
+		return binary.Write(s.output, binary.LittleEndian, value)
+	}
+
+	err = patch(4, s.riffChunkSize(s.written))
+	if err != nil {
+		return fmt.Errorf("patching riff chunk size: %s", err)
+	}
+
+	err = patch(s.dataszOff, s.written)
+	if err != nil {
+		return fmt.Errorf("patching data chunk size: %s", err)
+	}
+
+	_, err = s.seeker.Seek(end, io.SeekStart)
+	return err
+}
+
+// putInt24 encodes v as a 24 bit integer into the first 3 bytes of b.
+func putInt24(order binary.ByteOrder, b []byte, v int32) {
+	if order == binary.BigEndian {
+		b[0], b[1], b[2] = byte(v>>16), byte(v>>8), byte(v)
+		return
+	}
+	b[0], b[1], b[2] = byte(v), byte(v>>8), byte(v>>16)
+}
@@ -0,0 +1,102 @@
+package wave_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/NeowayLabs/signal/encoding/wave"
+)
+
+func TestStreamEncoderSeekable(t *testing.T) {
+	f, err := ioutil.TempFile("", "wave-stream")
+	assertNoError(t, err)
+
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	enc, err := wave.NewStreamEncoder(f, wave.NewPCM(1, 8000, 16))
+	assertNoError(t, err)
+
+	expected := []int16{}
+	for i := 0; i < 10; i++ {
+		block := []int16{int16(i), int16(-i), 255, -255}
+		assertNoError(t, enc.WriteInt16(block))
+		expected = append(expected, block...)
+	}
+	assertNoError(t, enc.Close())
+
+	_, err = f.Seek(0, io.SeekStart)
+	assertNoError(t, err)
+
+	got := []int16{}
+	hdr, err := wave.NewDecoder(f).DecodeInt16(&got)
+	assertNoError(t, err)
+
+	if hdr.DataBlockSize != uint32(2*len(expected)) {
+		t.Fatalf("data size differs: %d != %d", hdr.DataBlockSize, 2*len(expected))
+	}
+	if hdr.RiffHeader.ChunkSize != 36+hdr.DataBlockSize {
+		t.Fatalf("riff chunk size differs: %d != %d",
+			hdr.RiffHeader.ChunkSize, 36+hdr.DataBlockSize)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("samples differs: %v != %v", got, expected)
+	}
+}
+
+func TestStreamEncoderDeclaredSize(t *testing.T) {
+	samples := []float32{-1, -0.5, 0, 0.5, 1}
+
+	hdr := wave.NewIEEEFloat(1, 8000, 32)
+	hdr.DataBlockSize = uint32(4 * len(samples))
+
+	buf := &bytes.Buffer{}
+	enc, err := wave.NewStreamEncoder(buf, hdr)
+	assertNoError(t, err)
+	assertNoError(t, enc.WriteFloat32(samples[:2]))
+	assertNoError(t, enc.WriteFloat32(samples[2:]))
+	assertNoError(t, enc.Close())
+
+	got := []float32{}
+	_, err = wave.NewDecoder(buf).DecodeFloat32(&got)
+	assertNoError(t, err)
+
+	if !reflect.DeepEqual(got, samples) {
+		t.Fatalf("samples differs: %v != %v", got, samples)
+	}
+}
+
+func TestStreamEncoderDeclaredSizeMismatch(t *testing.T) {
+	hdr := wave.NewPCM(1, 8000, 16)
+	hdr.DataBlockSize = 10
+
+	enc, err := wave.NewStreamEncoder(&bytes.Buffer{}, hdr)
+	assertNoError(t, err)
+	assertNoError(t, enc.WriteInt16([]int16{1, 2}))
+	assertError(t, enc.Close())
+}
+
+func TestStreamEncoderUnknownSize(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc, err := wave.NewStreamEncoder(buf, wave.NewPCM(1, 8000, 16))
+	assertNoError(t, err)
+	assertNoError(t, enc.WriteInt16([]int16{1, 2, 3}))
+	assertNoError(t, enc.Close())
+
+	hdr, err := wave.DecodeHeader(bytes.NewReader(buf.Bytes()))
+	assertNoError(t, err)
+
+	if hdr.DataBlockSize != 0xFFFFFFFF || hdr.RiffHeader.ChunkSize != 0xFFFFFFFF {
+		t.Fatalf("expected streaming sizes, got riff[%x] data[%x]",
+			hdr.RiffHeader.ChunkSize, hdr.DataBlockSize)
+	}
+
+	err = enc.WriteInt16([]int16{4})
+	if err != wave.ErrClosed {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
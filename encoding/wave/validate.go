@@ -0,0 +1,163 @@
+package wave
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	// Severity of a header violation.
+	Severity int
+
+	// Violation is an inconsistency found in a header: the field
+	// has a value different from the expected one.
+	Violation struct {
+		Field    string
+		Expected string
+		Found    string
+		Severity Severity
+	}
+)
+
+const (
+	// SeverityWarning is a redundant field that disagrees with the
+	// others, that doesn't prevent the audio to be decoded.
+	SeverityWarning Severity = iota
+	// SeverityError is a field that makes the audio impossible to
+	// interpret.
+	SeverityError
+)
+
+// String returns the name of the severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return fmt.Sprintf("severity(%d)", int(s))
+}
+
+// String describes the violation.
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s: expected %s, found %s",
+		v.Severity, v.Field, v.Expected, v.Found)
+}
+
+// Validate checks the consistency of the header fields, returning the
+// violations found (none for valid headers). The fields derived from
+// others (eg. BytesPerSec) are checked against the values computed
+// from them. As the header doesn't know the size of the chunks other
+// than fmt and data, the ChunkSize is only checked to be big enough
+// to hold them.
+func (h Header) Validate() []Violation {
+	return h.validate(0)
+}
+
+// validate checks the header, where the size of the other chunks of
+// the file (with their headers) is known to be at least otherChunks.
+func (h Header) validate(otherChunks uint64) []Violation {
+	var vs []Violation
+	add := func(sev Severity, field string, expected, found interface{}) {
+		vs = append(vs, Violation{
+			Field:    field,
+			Expected: fmt.Sprint(expected),
+			Found:    fmt.Sprint(found),
+			Severity: sev,
+		})
+	}
+
+	if id := string(h.RiffHeader.Ident[:]); id != "RIFF" {
+		add(SeverityError, "RiffHeader.Ident", "RIFF", quote(id))
+	}
+	if ft := string(h.RiffHeader.FileType[:]); ft != "WAVE" {
+		add(SeverityError, "RiffHeader.FileType", "WAVE", quote(ft))
+	}
+
+	format := h.Format()
+	switch {
+	case h.AudioFormat == FormatExtensible:
+		if h.LengthOfHeader < 16+2+fmtExtensionSize {
+			add(SeverityError, "LengthOfHeader", fmt.Sprintf(">= %d", 16+2+fmtExtensionSize),
+				h.LengthOfHeader)
+		}
+		if format == FormatExtensible {
+			add(SeverityError, "Extension.SubFormat", "a known audio format",
+				h.Extension.SubFormat)
+		}
+		if h.Extension.ValidBitsPerSample > h.BitsPerSample {
+			add(SeverityWarning, "Extension.ValidBitsPerSample",
+				fmt.Sprintf("<= %d", h.BitsPerSample), h.Extension.ValidBitsPerSample)
+		}
+	case isValidWavFormat(h.AudioFormat):
+		if h.LengthOfHeader != 16 && h.LengthOfHeader < 18 {
+			add(SeverityError, "LengthOfHeader", "16 or >= 18", h.LengthOfHeader)
+		}
+	default:
+		add(SeverityError, "AudioFormat", "a known audio format", h.AudioFormat)
+	}
+
+	if h.NumChannels == 0 {
+		add(SeverityError, "NumChannels", "> 0", h.NumChannels)
+	}
+	if h.SampleRate == 0 {
+		add(SeverityError, "SampleRate", "> 0", h.SampleRate)
+	}
+
+	switch bits := h.BitsPerSample; format {
+	case FormatPCM:
+		if bits == 0 || bits > 32 {
+			add(SeverityError, "BitsPerSample", "1 to 32", bits)
+		}
+	case FormatIEEEFloat:
+		if bits != 32 && bits != 64 {
+			add(SeverityError, "BitsPerSample", "32 or 64", bits)
+		}
+	case FormatALAW, FormatMULAW:
+		if bits != 8 {
+			add(SeverityError, "BitsPerSample", 8, bits)
+		}
+	}
+
+	// computed in 64 bits, that don't overflow for any header
+	blocksz := uint64(h.NumChannels) * ((uint64(h.BitsPerSample) + 7) / 8)
+	if uint64(h.BytesPerBloc) != blocksz {
+		add(SeverityWarning, "BytesPerBloc", blocksz, h.BytesPerBloc)
+	}
+	if bytesPerSec := blocksz * uint64(h.SampleRate); uint64(h.BytesPerSec) != bytesPerSec {
+		add(SeverityWarning, "BytesPerSec", bytesPerSec, h.BytesPerSec)
+	}
+
+	if h.DataBlockSize == streamingSize || h.RiffHeader.ChunkSize == streamingSize {
+		// sizes unknown, written by a streaming encoder
+		return vs
+	}
+
+	if blocksz > 0 && uint64(h.DataBlockSize)%blocksz != 0 {
+		add(SeverityWarning, "DataBlockSize",
+			fmt.Sprintf("a multiple of %d", blocksz), h.DataBlockSize)
+	}
+
+	fmtsz := uint64(h.LengthOfHeader)
+	datasz := uint64(h.DataBlockSize)
+	minsz := 4 + (8 + fmtsz + fmtsz%2) + otherChunks + (8 + datasz + datasz%2)
+	if uint64(h.RiffHeader.ChunkSize) < minsz {
+		add(SeverityWarning, "RiffHeader.ChunkSize", fmt.Sprintf(">= %d", minsz),
+			h.RiffHeader.ChunkSize)
+	}
+	return vs
+}
+
+// validationError joins the violations into an error.
+func validationError(vs []Violation) error {
+	msgs := make([]string, len(vs))
+	for i, v := range vs {
+		msgs[i] = v.String()
+	}
+	return fmt.Errorf("invalid header: %s", strings.Join(msgs, "; "))
+}
+
+func quote(s string) string {
+	return fmt.Sprintf("%q", s)
+}
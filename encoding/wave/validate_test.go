@@ -0,0 +1,208 @@
+package wave_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NeowayLabs/signal/encoding/wave"
+)
+
+func findViolation(vs []wave.Violation, field string) (wave.Violation, bool) {
+	for _, v := range vs {
+		if v.Field == field {
+			return v, true
+		}
+	}
+	return wave.Violation{}, false
+}
+
+func TestValidateNewHeaders(t *testing.T) {
+	for _, hdr := range []wave.Header{
+		wave.NewPCM(1, 8000, 8),
+		wave.NewPCM(2, 44100, 16),
+		wave.NewPCM(6, 48000, 24),
+		wave.NewIEEEFloat(2, 48000, 32),
+		wave.NewALaw(1, 8000),
+		wave.NewMuLaw(2, 8000),
+		wave.NewExtensible(4, 96000, 20, wave.FormatPCM),
+	} {
+		hdr.RiffHeader.ChunkSize = 36 + 2*uint32(hdr.BytesPerBloc)
+		hdr.DataBlockSize = 2 * uint32(hdr.BytesPerBloc)
+		if hdr.AudioFormat == wave.FormatExtensible {
+			hdr.RiffHeader.ChunkSize += 24
+		}
+
+		if vs := hdr.Validate(); len(vs) != 0 {
+			t.Fatalf("unexpected violations for %#v: %v", hdr, vs)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		change   func(h *wave.Header)
+		field    string
+		severity wave.Severity
+		expected string
+		found    string
+	}{
+		{
+			name:     "bytes per second",
+			change:   func(h *wave.Header) { h.BytesPerSec = 8000 },
+			field:    "BytesPerSec",
+			severity: wave.SeverityWarning,
+			expected: "32000",
+			found:    "8000",
+		},
+		{
+			name:     "bytes per block",
+			change:   func(h *wave.Header) { h.BytesPerBloc = 2 },
+			field:    "BytesPerBloc",
+			severity: wave.SeverityWarning,
+			expected: "4",
+			found:    "2",
+		},
+		{
+			name:     "chunk size",
+			change:   func(h *wave.Header) { h.RiffHeader.ChunkSize = 100 },
+			field:    "RiffHeader.ChunkSize",
+			severity: wave.SeverityWarning,
+			expected: ">= 436",
+			found:    "100",
+		},
+		{
+			name:     "bytes per second overflow",
+			change:   func(h *wave.Header) { h.SampleRate = 1 << 30; h.BytesPerSec = 0 },
+			field:    "BytesPerSec",
+			severity: wave.SeverityWarning,
+			expected: "4294967296",
+			found:    "0",
+		},
+		{
+			name:     "chunk size overflow",
+			change:   func(h *wave.Header) { h.DataBlockSize = 0xFFFFFFFC },
+			field:    "RiffHeader.ChunkSize",
+			severity: wave.SeverityWarning,
+			expected: ">= 4294967328",
+			found:    "436",
+		},
+		{
+			name:     "partial frame",
+			change:   func(h *wave.Header) { h.DataBlockSize = 398; h.RiffHeader.ChunkSize = 434 },
+			field:    "DataBlockSize",
+			severity: wave.SeverityWarning,
+			expected: "a multiple of 4",
+			found:    "398",
+		},
+		{
+			name:     "no channels",
+			change:   func(h *wave.Header) { h.NumChannels = 0 },
+			field:    "NumChannels",
+			severity: wave.SeverityError,
+			expected: "> 0",
+			found:    "0",
+		},
+		{
+			name:     "no sample rate",
+			change:   func(h *wave.Header) { h.SampleRate = 0 },
+			field:    "SampleRate",
+			severity: wave.SeverityError,
+			expected: "> 0",
+			found:    "0",
+		},
+		{
+			name:     "G.711 bits",
+			change:   func(h *wave.Header) { h.AudioFormat = wave.FormatALAW },
+			field:    "BitsPerSample",
+			severity: wave.SeverityError,
+			expected: "8",
+			found:    "16",
+		},
+		{
+			name:     "file type",
+			change:   func(h *wave.Header) { h.RiffHeader.FileType = [4]byte{'A', 'V', 'I', ' '} },
+			field:    "RiffHeader.FileType",
+			severity: wave.SeverityError,
+			expected: "WAVE",
+			found:    `"AVI "`,
+		},
+	} {
+		hdr := wave.NewPCM(2, 8000, 16)
+		hdr.DataBlockSize = 400
+		hdr.RiffHeader.ChunkSize = 436
+		tc.change(&hdr)
+
+		v, ok := findViolation(hdr.Validate(), tc.field)
+		if !ok {
+			t.Fatalf("%s: violation not found: %v", tc.name, hdr.Validate())
+		}
+		if v.Severity != tc.severity || v.Expected != tc.expected || v.Found != tc.found {
+			t.Fatalf("%s: unexpected violation: %s", tc.name, v)
+		}
+	}
+}
+
+func TestValidateStreaming(t *testing.T) {
+	hdr := wave.NewPCM(1, 8000, 16)
+	hdr.RiffHeader.ChunkSize = 0xFFFFFFFF
+	hdr.DataBlockSize = 0xFFFFFFFF
+
+	if vs := hdr.Validate(); len(vs) != 0 {
+		t.Fatalf("unexpected violations: %v", vs)
+	}
+}
+
+func TestValidateTestdata(t *testing.T) {
+	files, err := filepath.Glob("testdata/*.wav")
+	assertNoError(t, err)
+
+	for _, fname := range files {
+		f, err := os.Open(fname)
+		assertNoError(t, err)
+
+		d := wave.NewDecoder(f)
+		d.Strict()
+		_, err = d.DecodeHeader()
+		f.Close()
+
+		if err != nil || len(d.Violations()) != 0 {
+			t.Fatalf("%s: unexpected violations: %s: %v", fname, err, d.Violations())
+		}
+	}
+}
+
+func TestDecoderStrictMode(t *testing.T) {
+	audio, err := wave.NewEncoder(wave.NewPCM(2, 8000, 16)).EncodeInt16([]int16{1, 2, 3, 4})
+	assertNoError(t, err)
+
+	// BytesPerSec at offset 28
+	binary.LittleEndian.PutUint32(audio[28:], 1234)
+
+	d := wave.NewDecoder(bytes.NewReader(audio))
+	var samples []int16
+	_, err = d.DecodeInt16(&samples)
+	assertNoError(t, err)
+	if len(samples) != 4 {
+		t.Fatalf("unexpected samples: %v", samples)
+	}
+	if _, ok := findViolation(d.Violations(), "BytesPerSec"); !ok || len(d.Violations()) != 1 {
+		t.Fatalf("unexpected warnings: %v", d.Violations())
+	}
+
+	d = wave.NewDecoder(bytes.NewReader(audio))
+	d.Strict()
+	_, err = d.DecodeInt16(&samples)
+	assertError(t, err)
+
+	d = wave.NewDecoder(bytes.NewReader(audio))
+	d.Strict()
+	hdr, err := d.DecodeHeader()
+	assertError(t, err)
+	if hdr.BytesPerSec != 1234 {
+		t.Fatalf("the invalid header must be returned for inspection: %#v", hdr)
+	}
+}
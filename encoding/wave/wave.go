@@ -1,6 +1,11 @@
 package wave
 
-import "io"
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
 
 type (
 	// Header of Wave
@@ -8,6 +13,10 @@ type (
 		RiffHeader RiffHeader
 		RiffChunkFmt
 
+		// Extension of the fmt chunk, only present when AudioFormat
+		// is FormatExtensible.
+		Extension FmtExtension
+
 		DataBlockSize uint32 // size of sample data (PCM data)
 	}
 
@@ -27,8 +36,22 @@ type (
 		BytesPerBloc   uint16
 		BitsPerSample  uint16
 	}
+
+	// FmtExtension is the extension of the fmt chunk of
+	// WAVE_FORMAT_EXTENSIBLE files.
+	FmtExtension struct {
+		ValidBitsPerSample uint16 // bits of precision in the container
+		ChannelMask        uint32 // speaker positions of the channels
+		SubFormat          GUID   // the real format of the data
+	}
+
+	// GUID identifies the SubFormat of extensible WAVE files.
+	GUID [16]byte
 )
 
+// fmtExtensionSize is the value of cbSize for extensible formats
+const fmtExtensionSize = 22
+
 const (
 	FormatPCM        = 0x0001
 	FormatIEEEFloat  = 0x0003
@@ -37,38 +60,197 @@ const (
 	FormatExtensible = 0xFFFE
 )
 
-// NewPCM creates a new PCM wave header
+// newChunkFmt creates a fmt chunk with the block alignment and byte
+// rate computed from the number of channels and the bytes needed to
+// store each sample.
+func newChunkFmt(format uint16, nchannels, samplerate, bits int) RiffChunkFmt {
+	blocksz := nchannels * ((bits + 7) / 8)
+	return RiffChunkFmt{
+		LengthOfHeader: 16,
+		AudioFormat:    format,
+		NumChannels:    uint16(nchannels),
+		SampleRate:     uint32(samplerate),
+		BytesPerSec:    uint32(blocksz) * uint32(samplerate),
+		BytesPerBloc:   uint16(blocksz),
+		BitsPerSample:  uint16(bits),
+	}
+}
+
+// NewPCM creates a new PCM wave header.
+// Use 8 bits for unsigned samples and 16, 24 or 32 bits for signed
+// samples.
 func NewPCM(nchannels, samplerate, bits int) Header {
 	return Header{
-		RiffHeader: waveRiff(),
-		RiffChunkFmt: RiffChunkFmt{
-			LengthOfHeader: 16,
-			AudioFormat:    FormatPCM,
-			NumChannels:    uint16(nchannels),
-			SampleRate:     uint32(samplerate),
-			BytesPerSec:    uint32(bits/8) * uint32(samplerate),
-			BytesPerBloc:   uint16(bits / 8),
-			BitsPerSample:  uint16(bits),
-		},
+		RiffHeader:   waveRiff(),
+		RiffChunkFmt: newChunkFmt(FormatPCM, nchannels, samplerate, bits),
 	}
 }
 
 // NewIEEEFloat creates a new WAVE storing IEEE float data
 func NewIEEEFloat(nchannels, samplerate, bits int) Header {
 	return Header{
-		RiffHeader: waveRiff(),
-		RiffChunkFmt: RiffChunkFmt{
-			LengthOfHeader: 16,
-			AudioFormat:    FormatIEEEFloat,
-			NumChannels:    uint16(nchannels),
-			SampleRate:     uint32(samplerate),
-			BytesPerSec:    uint32(bits/8) * uint32(samplerate),
-			BytesPerBloc:   uint16(bits / 8),
-			BitsPerSample:  uint16(bits),
+		RiffHeader:   waveRiff(),
+		RiffChunkFmt: newChunkFmt(FormatIEEEFloat, nchannels, samplerate, bits),
+	}
+}
+
+// NewALaw creates a new WAVE storing 8 bit G.711 A-law data
+func NewALaw(nchannels, samplerate int) Header {
+	return Header{
+		RiffHeader:   waveRiff(),
+		RiffChunkFmt: newChunkFmt(FormatALAW, nchannels, samplerate, 8),
+	}
+}
+
+// NewMuLaw creates a new WAVE storing 8 bit G.711 μ-law data
+func NewMuLaw(nchannels, samplerate int) Header {
+	return Header{
+		RiffHeader:   waveRiff(),
+		RiffChunkFmt: newChunkFmt(FormatMULAW, nchannels, samplerate, 8),
+	}
+}
+
+// NewExtensible creates a new WAVE_FORMAT_EXTENSIBLE header storing
+// data of the given format (FormatPCM, FormatIEEEFloat, etc).
+// The sample container is rounded up to whole bytes and the channel
+// mask is set to the default speaker positions for nchannels.
+func NewExtensible(nchannels, samplerate, bits int, format uint16) Header {
+	chunkFmt := newChunkFmt(FormatExtensible, nchannels, samplerate, (bits+7)/8*8)
+	chunkFmt.LengthOfHeader = 16 + 2 + fmtExtensionSize
+	return Header{
+		RiffHeader:   waveRiff(),
+		RiffChunkFmt: chunkFmt,
+		Extension: FmtExtension{
+			ValidBitsPerSample: uint16(bits),
+			ChannelMask:        defaultChannelMask(nchannels),
+			SubFormat:          SubFormatGUID(format),
 		},
 	}
 }
 
+// Format returns the format of the data samples. For extensible
+// headers, it's the format identified by the SubFormat GUID (or
+// FormatExtensible if the GUID is unknown).
+func (h Header) Format() uint16 {
+	if h.AudioFormat != FormatExtensible {
+		return h.AudioFormat
+	}
+	if format, ok := h.Extension.SubFormat.Format(); ok {
+		return format
+	}
+	return FormatExtensible
+}
+
+// SetSampleRate changes the sample rate of the header, updating the
+// BytesPerSec. Use it to encode a resampled signal with the format of
+// the original file. It fails if the byte rate doesn't fit in the
+// header.
+func (h *Header) SetSampleRate(samplerate int) error {
+	return h.setLayout(int(h.NumChannels), samplerate)
+}
+
+// setLayout changes the number of channels and the sample rate of the
+// header, updating the fields derived from them.
+func (h *Header) setLayout(nchannels, samplerate int) error {
+	blocksz, err := blockSize(nchannels, int(h.BitsPerSample))
+	if err != nil {
+		return err
+	}
+	bytesPerSec := uint64(blocksz) * uint64(samplerate)
+	if samplerate <= 0 || bytesPerSec > math.MaxUint32 {
+		return fmt.Errorf("invalid sample rate: %d", samplerate)
+	}
+
+	if h.AudioFormat == FormatExtensible && int(h.NumChannels) != nchannels {
+		h.Extension.ChannelMask = defaultChannelMask(nchannels)
+	}
+
+	h.NumChannels = uint16(nchannels)
+	h.SampleRate = uint32(samplerate)
+	h.BytesPerBloc = uint16(blocksz)
+	h.BytesPerSec = uint32(bytesPerSec)
+	return nil
+}
+
+// blockSize returns the size in bytes of a frame with nchannels
+// samples of bits each. It fails if the size doesn't fit in the
+// BytesPerBloc field.
+func blockSize(nchannels, bits int) (int, error) {
+	if nchannels <= 0 || nchannels > math.MaxUint16 {
+		return 0, fmt.Errorf("invalid number of channels: %d", nchannels)
+	}
+	blocksz := nchannels * ((bits + 7) / 8)
+	if blocksz <= 0 || blocksz > math.MaxUint16 {
+		return 0, fmt.Errorf("invalid block size: channels[%d], bits[%d]", nchannels, bits)
+	}
+	return blocksz, nil
+}
+
+// needsExtensible tells if the header must be written as
+// WAVE_FORMAT_EXTENSIBLE, ie. more than 2 channels or PCM data with
+// more than 16 bits per sample.
+func (h Header) needsExtensible() bool {
+	switch h.AudioFormat {
+	case FormatPCM:
+		return h.NumChannels > 2 || h.BitsPerSample > 16
+	case FormatIEEEFloat:
+		return h.NumChannels > 2
+	}
+	return false
+}
+
+// extensible converts the header into WAVE_FORMAT_EXTENSIBLE.
+func (h Header) extensible() Header {
+	h.Extension = FmtExtension{
+		ValidBitsPerSample: h.BitsPerSample,
+		ChannelMask:        defaultChannelMask(int(h.NumChannels)),
+		SubFormat:          SubFormatGUID(h.AudioFormat),
+	}
+	h.AudioFormat = FormatExtensible
+	h.LengthOfHeader = 16 + 2 + fmtExtensionSize
+	return h
+}
+
+// defaultChannelMask returns the speaker positions commonly used for
+// nchannels (front left, front right, front center, etc).
+func defaultChannelMask(nchannels int) uint32 {
+	if nchannels <= 0 || nchannels > 18 {
+		// 18 speaker positions are defined
+		return 0
+	}
+	return 1<<uint(nchannels) - 1
+}
+
+// SubFormatGUID returns the SubFormat GUID of the WAVE format code.
+// The GUIDs have the form 0000XXXX-0000-0010-8000-00AA00389B71, where
+// XXXX is the format code.
+func SubFormatGUID(format uint16) GUID {
+	return GUID{
+		byte(format), byte(format >> 8), 0x00, 0x00,
+		0x00, 0x00, 0x10, 0x00,
+		0x80, 0x00, 0x00, 0xAA, 0x00, 0x38, 0x9B, 0x71,
+	}
+}
+
+// Format returns the WAVE format code of the SubFormat GUID, if the
+// GUID is one of the well known formats.
+func (g GUID) Format() (uint16, bool) {
+	format := uint16(g[0]) | uint16(g[1])<<8
+	if g != SubFormatGUID(format) || !isValidWavFormat(format) {
+		return 0, false
+	}
+	return format, true
+}
+
+// String returns the GUID in the canonical textual form.
+func (g GUID) String() string {
+	return fmt.Sprintf("%08X-%04X-%04X-%X-%X",
+		binary.LittleEndian.Uint32(g[0:4]),
+		binary.LittleEndian.Uint16(g[4:6]),
+		binary.LittleEndian.Uint16(g[6:8]),
+		g[8:10], g[10:16])
+}
+
 func waveRiff() RiffHeader {
 	return RiffHeader{
 		Ident:    [4]byte{'R', 'I', 'F', 'F'},
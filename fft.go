@@ -0,0 +1,231 @@
+package signal
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+)
+
+// DFT calculates the discrete Fourier transform of x by correlation
+// (DSP Book, Chapter 8). It takes O(N²) operations, use FFT instead.
+// X(k) = Σx(n)·e^(-i2πkn/N)
+func DFT(x []complex128) []complex128 {
+	n := len(x)
+	out := make([]complex128, n)
+	for k := range out {
+		var sum complex128
+		for i, v := range x {
+			// reduces k*i modulo n to keep the angle precise
+			angle := -2 * math.Pi * float64((k*i)%n) / float64(n)
+			sum += v * cmplx.Rect(1, angle)
+		}
+		out[k] = sum
+	}
+	return out
+}
+
+// FFT calculates the discrete Fourier transform of x using the fast
+// Fourier transform (DSP Book, Chapter 12).
+// Lengths that are power of two use the radix-2 algorithm, other
+// lengths use the Bluestein (chirp-z) algorithm.
+func FFT(x []complex128) []complex128 {
+	out := make([]complex128, len(x))
+	copy(out, x)
+
+	if len(out) == 0 {
+		return out
+	}
+	if isPowerOf2(len(out)) {
+		radix2(out, false)
+		return out
+	}
+	return bluestein(out)
+}
+
+// IFFT calculates the inverse discrete Fourier transform of X, such
+// that IFFT(FFT(x)) == x.
+func IFFT(X []complex128) []complex128 {
+	n := len(X)
+	if n == 0 {
+		return []complex128{}
+	}
+
+	tmp := make([]complex128, n)
+	for i, v := range X {
+		tmp[i] = cmplx.Conj(v)
+	}
+
+	out := FFT(tmp)
+	for i, v := range out {
+		out[i] = cmplx.Conj(v) / complex(float64(n), 0)
+	}
+	return out
+}
+
+// RealFFT calculates the spectrum of the real signal sig, returning
+// only the N/2+1 non-redundant bins (from DC to the Nyquist
+// frequency), because the spectrum of a real signal is symmetric.
+func RealFFT(sig Discrete) []complex128 {
+	x := make([]complex128, len(sig))
+	for i, v := range sig {
+		x[i] = complex(v, 0)
+	}
+
+	if len(x) == 0 {
+		return []complex128{}
+	}
+	return FFT(x)[:len(x)/2+1]
+}
+
+// InverseRealFFT calculates the real signal of length n from the
+// N/2+1 bins of its spectrum, as returned by RealFFT. It fails if X
+// has fewer bins.
+func InverseRealFFT(X []complex128, n int) (Discrete, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("invalid signal length: %d", n)
+	}
+	if n == 0 {
+		return Discrete{}, nil
+	}
+	if len(X) < n/2+1 {
+		return nil, fmt.Errorf("%d bins can't be inverted into %d samples, need %d",
+			len(X), n, n/2+1)
+	}
+
+	full := make([]complex128, n)
+	copy(full, X)
+	// rebuilds the negative frequencies by symmetry
+	for k := n/2 + 1; k < n; k++ {
+		full[k] = cmplx.Conj(X[n-k])
+	}
+
+	x := IFFT(full)
+	out := make(Discrete, n)
+	for i, v := range x {
+		out[i] = real(v)
+	}
+	return out, nil
+}
+
+// Magnitude returns the magnitude (|X|) of each bin of the spectrum.
+func Magnitude(X []complex128) Discrete {
+	out := make(Discrete, len(X))
+	for i, v := range X {
+		out[i] = cmplx.Abs(v)
+	}
+	return out
+}
+
+// Phase returns the phase (in radians) of each bin of the spectrum.
+func Phase(X []complex128) Discrete {
+	out := make(Discrete, len(X))
+	for i, v := range X {
+		out[i] = cmplx.Phase(v)
+	}
+	return out
+}
+
+// FrequencyBins returns the frequency (in hertz) of the N/2+1 bins
+// returned by RealFFT for a signal of n samples sampled at samplerate.
+func FrequencyBins(n int, samplerate float64) Discrete {
+	if n == 0 {
+		return Discrete{}
+	}
+
+	out := make(Discrete, n/2+1)
+	for k := range out {
+		out[k] = float64(k) * samplerate / float64(n)
+	}
+	return out
+}
+
+func isPowerOf2(n int) bool {
+	return n > 0 && n&(n-1) == 0
+}
+
+// nextPowerOf2 returns the smallest power of two >= n.
+func nextPowerOf2(n int) int {
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
+
+// radix2 calculates the FFT of x in place. The length of x must be a
+// power of two. If inverse is true, the unnormalized inverse
+// transform is calculated.
+func radix2(x []complex128, inverse bool) {
+	n := len(x)
+
+	// bit reversal sorting
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+
+	sign := -1.0
+	if inverse {
+		sign = 1.0
+	}
+
+	for size := 2; size <= n; size <<= 1 {
+		half := size / 2
+		step := cmplx.Rect(1, sign*2*math.Pi/float64(size))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < half; k++ {
+				u := x[start+k]
+				v := x[start+k+half] * w
+				x[start+k] = u + v
+				x[start+k+half] = u - v
+				w *= step
+			}
+		}
+	}
+}
+
+// bluestein calculates the FFT of x of arbitrary length by expressing
+// the DFT as a convolution, that is calculated with radix-2 FFTs.
+func bluestein(x []complex128) []complex128 {
+	n := len(x)
+	m := nextPowerOf2(2*n - 1)
+
+	// chirp: w(k) = e^(-iπk²/N)
+	chirp := make([]complex128, n)
+	for k := range chirp {
+		kk := (k * k) % (2 * n)
+		chirp[k] = cmplx.Rect(1, -math.Pi*float64(kk)/float64(n))
+	}
+
+	a := make([]complex128, m)
+	for k, v := range x {
+		a[k] = v * chirp[k]
+	}
+
+	b := make([]complex128, m)
+	b[0] = cmplx.Conj(chirp[0])
+	for k := 1; k < n; k++ {
+		b[k] = cmplx.Conj(chirp[k])
+		b[m-k] = b[k]
+	}
+
+	radix2(a, false)
+	radix2(b, false)
+	for i := range a {
+		a[i] *= b[i]
+	}
+	radix2(a, true)
+
+	out := make([]complex128, n)
+	for k := range out {
+		out[k] = a[k] * chirp[k] / complex(float64(m), 0)
+	}
+	return out
+}
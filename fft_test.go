@@ -0,0 +1,96 @@
+package signal_test
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/NeowayLabs/signal"
+)
+
+func complexSignal(n int) []complex128 {
+	x := make([]complex128, n)
+	for i := range x {
+		x[i] = complex(math.Sin(float64(i)*0.7)+float64(i%3), math.Cos(float64(i)*1.3))
+	}
+	return x
+}
+
+func assertComplexAlmost(t *testing.T, got, expected []complex128, ε float64, msg string) {
+	t.Helper()
+	assert(t, len(got) == len(expected), fmt("%s: length differs: %d != %d",
+		msg, len(got), len(expected)))
+	for i := range got {
+		assert(t, cmplx.Abs(got[i]-expected[i]) <= ε,
+			fmt("%s: bin[%d] differs: %v != %v", msg, i, got[i], expected[i]))
+	}
+}
+
+func TestFFT(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 5, 7, 8, 12, 16, 17, 100, 128} {
+		x := complexSignal(n)
+		assertComplexAlmost(t, signal.FFT(x), signal.DFT(x), 1e-9, fmt("fft n=%d", n))
+	}
+}
+
+func TestIFFT(t *testing.T) {
+	for _, n := range []int{1, 6, 64, 99} {
+		x := complexSignal(n)
+		assertComplexAlmost(t, signal.IFFT(signal.FFT(x)), x, 1e-9, fmt("ifft n=%d", n))
+	}
+	assert(t, len(signal.FFT(nil)) == 0, "empty fft")
+	assert(t, len(signal.IFFT(nil)) == 0, "empty ifft")
+}
+
+func TestRealFFT(t *testing.T) {
+	const (
+		n          = 200
+		samplerate = 8000.0
+		freq       = 1000.0
+	)
+
+	sig := make(signal.Discrete, n)
+	for i := range sig {
+		sig[i] = 0.5 + math.Sin(2*math.Pi*freq*float64(i)/samplerate)
+	}
+
+	X := signal.RealFFT(sig)
+	assert(t, len(X) == n/2+1, "real fft length")
+
+	bins := signal.FrequencyBins(n, samplerate)
+	assert(t, len(bins) == len(X), "bins length")
+
+	mag := signal.Magnitude(X)
+	assertAlmost(t, mag[0], 0.5*n, 1e-9, "DC magnitude")
+
+	peak := 1
+	for k := 1; k < len(mag); k++ {
+		if mag[k] > mag[peak] {
+			peak = k
+		}
+	}
+	assertAlmost(t, bins[peak], freq, 1e-9, "peak frequency")
+	assertAlmost(t, mag[peak], n/2, 1e-9, "peak magnitude")
+	assertAlmost(t, signal.Phase(X)[peak], -math.Pi/2, 1e-9, "sine phase")
+
+	back, err := signal.InverseRealFFT(X, n)
+	assert(t, err == nil, "inverse real fft must not fail")
+	for i := range sig {
+		assertAlmost(t, back[i], sig[i], 1e-9, "inverse real fft")
+	}
+
+	_, err = signal.InverseRealFFT(X[:len(X)-1], n)
+	assert(t, err != nil, "missing bins must fail")
+}
+
+func TestParseval(t *testing.T) {
+	x := complexSignal(37)
+	X := signal.FFT(x)
+
+	var et, ef float64
+	for i := range x {
+		et += math.Pow(cmplx.Abs(x[i]), 2)
+		ef += math.Pow(cmplx.Abs(X[i]), 2)
+	}
+	assertAlmost(t, et, ef/float64(len(x)), 1e-9, "energy")
+}
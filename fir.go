@@ -0,0 +1,187 @@
+package signal
+
+import (
+	"fmt"
+	"math"
+)
+
+type (
+	// Filter processes a signal in blocks, carrying its state across
+	// calls, such that processing a signal block by block gives the
+	// same result of processing the whole signal at once.
+	Filter interface {
+		// Process filters the block, returning the output with the
+		// same length of the block.
+		Process(block Discrete) Discrete
+
+		// Reset clears the state of the filter.
+		Reset()
+	}
+
+	// FIR is a finite impulse response filter, that convolves the
+	// input with its kernel (impulse response).
+	FIR struct {
+		kernel  Discrete
+		history Discrete // last len(kernel)-1 input samples
+	}
+)
+
+// NewFIR creates a new FIR filter with the given kernel.
+func NewFIR(kernel Discrete) *FIR {
+	f := &FIR{
+		kernel: kernel,
+	}
+	f.Reset()
+	return f
+}
+
+// Process filters the block, returning the output with the same
+// length of the block.
+func (f *FIR) Process(block Discrete) Discrete {
+	m := len(f.kernel)
+	if m == 0 {
+		return make(Discrete, len(block))
+	}
+
+	// input = history followed by the block
+	input := make(Discrete, 0, len(f.history)+len(block))
+	input = append(input, f.history...)
+	input = append(input, block...)
+
+	out := make(Discrete, len(block))
+	for i := range out {
+		var sum float64
+		// y(n) = Σh(k)·x(n-k)
+		n := i + m - 1
+		for k, h := range f.kernel {
+			sum += h * input[n-k]
+		}
+		out[i] = sum
+	}
+
+	copy(f.history, input[len(input)-(m-1):])
+	return out
+}
+
+// Reset clears the input history of the filter.
+func (f *FIR) Reset() {
+	n := len(f.kernel) - 1
+	if n < 0 {
+		n = 0
+	}
+	f.history = make(Discrete, n)
+}
+
+// FilterFIR filters the signal x with the kernel, returning the
+// first len(x) samples of the convolution (ie. the causal output,
+// delayed by the group delay of the kernel).
+func FilterFIR(x, kernel Discrete) Discrete {
+	if len(kernel) == 0 {
+		return make(Discrete, len(x))
+	}
+	return FastConvolve(x, kernel)[:len(x)]
+}
+
+// LowPass designs a low-pass FIR kernel with length taps by the
+// windowed-sinc method (DSP Book, Chapter 16), using the Blackman
+// window. The cutoff frequency is given in hertz and must be below
+// the Nyquist frequency (samplerate/2). The length must be odd.
+// The kernel is normalized for unity gain at DC.
+func LowPass(cutoff, samplerate float64, length int) (Discrete, error) {
+	fc, err := normalizedCutoff(cutoff, samplerate)
+	if err != nil {
+		return nil, err
+	}
+	if length < 3 || length%2 == 0 {
+		return nil, fmt.Errorf("kernel length must be odd and >= 3: %d", length)
+	}
+
+	m := length - 1
+	window := Blackman(length)
+
+	kernel := make(Discrete, length)
+	var sum float64
+	for i := range kernel {
+		if i == m/2 {
+			kernel[i] = 2 * math.Pi * fc
+		} else {
+			x := float64(i - m/2)
+			kernel[i] = math.Sin(2*math.Pi*fc*x) / x
+		}
+		kernel[i] *= window[i]
+		sum += kernel[i]
+	}
+
+	for i := range kernel {
+		kernel[i] /= sum
+	}
+	return kernel, nil
+}
+
+// HighPass designs a high-pass FIR kernel by spectral inversion of
+// a low-pass kernel. It has the same requirements of LowPass.
+func HighPass(cutoff, samplerate float64, length int) (Discrete, error) {
+	kernel, err := LowPass(cutoff, samplerate, length)
+	if err != nil {
+		return nil, err
+	}
+	return invert(kernel), nil
+}
+
+// BandStop designs a band-stop (band-reject) FIR kernel rejecting the
+// frequencies between low and high, adding a low-pass and a high-pass
+// kernel. It has the same requirements of LowPass.
+func BandStop(low, high, samplerate float64, length int) (Discrete, error) {
+	if low >= high {
+		return nil, fmt.Errorf("invalid band: low[%f] >= high[%f]", low, high)
+	}
+
+	lp, err := LowPass(low, samplerate, length)
+	if err != nil {
+		return nil, err
+	}
+	hp, err := HighPass(high, samplerate, length)
+	if err != nil {
+		return nil, err
+	}
+
+	kernel := make(Discrete, length)
+	for i := range kernel {
+		kernel[i] = lp[i] + hp[i]
+	}
+	return kernel, nil
+}
+
+// BandPass designs a band-pass FIR kernel passing the frequencies
+// between low and high, by spectral inversion of a band-stop kernel.
+// It has the same requirements of LowPass.
+func BandPass(low, high, samplerate float64, length int) (Discrete, error) {
+	kernel, err := BandStop(low, high, samplerate, length)
+	if err != nil {
+		return nil, err
+	}
+	return invert(kernel), nil
+}
+
+// normalizedCutoff returns the cutoff as a fraction of the sample rate.
+func normalizedCutoff(cutoff, samplerate float64) (float64, error) {
+	if samplerate <= 0 {
+		return 0, fmt.Errorf("invalid sample rate: %f", samplerate)
+	}
+	if cutoff <= 0 || cutoff >= samplerate/2 {
+		return 0, fmt.Errorf("cutoff frequency must be between 0 and %f: %f",
+			samplerate/2, cutoff)
+	}
+	return cutoff / samplerate, nil
+}
+
+// invert applies spectral inversion to the symmetric kernel of odd
+// length, flipping its frequency response top-down.
+func invert(kernel Discrete) Discrete {
+	out := make(Discrete, len(kernel))
+	for i, v := range kernel {
+		out[i] = -v
+	}
+	out[len(out)/2]++
+	return out
+}
@@ -0,0 +1,89 @@
+package signal_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/NeowayLabs/signal"
+)
+
+// gain returns the gain of the kernel at the frequency f (fraction of
+// the sample rate).
+func gain(kernel signal.Discrete, f float64) float64 {
+	var re, im float64
+	for i, v := range kernel {
+		re += v * math.Cos(2*math.Pi*f*float64(i))
+		im -= v * math.Sin(2*math.Pi*f*float64(i))
+	}
+	return math.Hypot(re, im)
+}
+
+func TestFIRDesign(t *testing.T) {
+	const rate = 8000.0
+
+	lp, err := signal.LowPass(1000, rate, 101)
+	assert(t, err == nil, fmt("low-pass: %v", err))
+	hp, err := signal.HighPass(1000, rate, 101)
+	assert(t, err == nil, fmt("high-pass: %v", err))
+	bp, err := signal.BandPass(1000, 2000, rate, 101)
+	assert(t, err == nil, fmt("band-pass: %v", err))
+	bs, err := signal.BandStop(1000, 2000, rate, 101)
+	assert(t, err == nil, fmt("band-stop: %v", err))
+
+	for _, tc := range []struct {
+		name   string
+		kernel signal.Discrete
+		freq   float64
+		gain   float64
+	}{
+		{"low-pass passband", lp, 200, 1},
+		{"low-pass stopband", lp, 2000, 0},
+		{"high-pass passband", hp, 3000, 1},
+		{"high-pass stopband", hp, 200, 0},
+		{"band-pass passband", bp, 1500, 1},
+		{"band-pass low stopband", bp, 300, 0},
+		{"band-pass high stopband", bp, 3000, 0},
+		{"band-stop stopband", bs, 1500, 0},
+		{"band-stop low passband", bs, 300, 1},
+		{"band-stop high passband", bs, 3000, 1},
+	} {
+		assertAlmost(t, gain(tc.kernel, tc.freq/rate), tc.gain, 1e-3, tc.name)
+	}
+
+	// cutoff is at half amplitude
+	assertAlmost(t, gain(lp, 1000/rate), 0.5, 1e-2, "low-pass cutoff")
+}
+
+func TestFIRDesignInvalid(t *testing.T) {
+	_, err := signal.LowPass(1000, 8000, 100)
+	assert(t, err != nil, "even length must fail")
+
+	_, err = signal.LowPass(4000, 8000, 101)
+	assert(t, err != nil, "cutoff at nyquist must fail")
+
+	_, err = signal.BandPass(2000, 1000, 8000, 101)
+	assert(t, err != nil, "inverted band must fail")
+}
+
+func TestFIRStreaming(t *testing.T) {
+	kernel, err := signal.LowPass(500, 8000, 31)
+	assert(t, err == nil, fmt("low-pass: %v", err))
+
+	x := testSignal(1000)
+	expected := signal.FilterFIR(x, kernel)
+	assertDiscreteAlmost(t, expected, signal.Convolve(x, kernel)[:len(x)], 1e-9, "filter")
+
+	f := signal.NewFIR(kernel)
+	var got signal.Discrete
+	for start := 0; start < len(x); start += 77 {
+		end := start + 77
+		if end > len(x) {
+			end = len(x)
+		}
+		got = append(got, f.Process(x[start:end])...)
+	}
+	assertDiscreteAlmost(t, got, expected, 1e-9, "streaming")
+
+	f.Reset()
+	assertDiscreteAlmost(t, f.Process(x), expected, 1e-9, "after reset")
+}
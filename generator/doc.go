@@ -0,0 +1,5 @@
+// Package generator synthesizes test signals: periodic waveforms,
+// chirps, DTMF tones, noises, impulses and steps.
+// Every generator samples the signal at Params.SampleRate for
+// Params.Duration seconds, returning a signal.Discrete.
+package generator
@@ -0,0 +1,323 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/NeowayLabs/signal"
+)
+
+type (
+	// Params are the parameters shared by the generators.
+	Params struct {
+		SampleRate float64 // in hertz
+		Duration   float64 // in seconds
+		Amplitude  float64 // peak amplitude
+		Phase      float64 // initial phase of periodic signals, in radians
+	}
+
+	// Sweep is how the frequency of a chirp changes over time.
+	Sweep int
+)
+
+const (
+	// LinearSweep changes the frequency at a constant rate (Hz/s).
+	LinearSweep Sweep = iota
+	// LogSweep changes the frequency exponentially, spending the
+	// same time in every octave.
+	LogSweep
+)
+
+// dtmfKeys maps the keys of the DTMF keypad to their row and column.
+var dtmfKeys = map[rune][2]int{
+	'1': {0, 0}, '2': {0, 1}, '3': {0, 2}, 'A': {0, 3},
+	'4': {1, 0}, '5': {1, 1}, '6': {1, 2}, 'B': {1, 3},
+	'7': {2, 0}, '8': {2, 1}, '9': {2, 2}, 'C': {2, 3},
+	'*': {3, 0}, '0': {3, 1}, '#': {3, 2}, 'D': {3, 3},
+}
+
+var (
+	dtmfRows = [4]float64{697, 770, 852, 941}
+	dtmfCols = [4]float64{1209, 1336, 1477, 1633}
+)
+
+// Sine generates a sine wave of freq hertz.
+func Sine(p Params, freq float64) (signal.Discrete, error) {
+	return periodic(p, freq, func(x float64) float64 {
+		return math.Sin(2 * math.Pi * x)
+	})
+}
+
+// Square generates a square wave of freq hertz, that is +Amplitude in
+// the first half of the cycle and -Amplitude in the second half.
+func Square(p Params, freq float64) (signal.Discrete, error) {
+	return periodic(p, freq, func(x float64) float64 {
+		if x < 0.5 {
+			return 1
+		}
+		return -1
+	})
+}
+
+// Sawtooth generates a sawtooth wave of freq hertz, rising from
+// -Amplitude to +Amplitude in every cycle. Like the sine, it's zero at
+// the start of the cycle.
+func Sawtooth(p Params, freq float64) (signal.Discrete, error) {
+	return periodic(p, freq, func(x float64) float64 {
+		return 2*frac(x+0.5) - 1
+	})
+}
+
+// Triangle generates a triangle wave of freq hertz, following the
+// sign of the sine with the same frequency and phase.
+func Triangle(p Params, freq float64) (signal.Discrete, error) {
+	return periodic(p, freq, func(x float64) float64 {
+		switch {
+		case x < 0.25:
+			return 4 * x
+		case x < 0.75:
+			return 2 - 4*x
+		}
+		return 4*x - 4
+	})
+}
+
+// Pulse generates a train of rectangular pulses of freq hertz, that
+// are Amplitude high for the duty fraction (0 to 1) of the cycle and
+// zero for the rest.
+func Pulse(p Params, freq, duty float64) (signal.Discrete, error) {
+	if duty < 0 || duty > 1 {
+		return nil, fmt.Errorf("invalid duty cycle: %f", duty)
+	}
+	return periodic(p, freq, func(x float64) float64 {
+		if x < duty {
+			return 1
+		}
+		return 0
+	})
+}
+
+// Chirp generates a sine wave sweeping the frequency from the from
+// hertz at the start to the to hertz at the end of the duration.
+func Chirp(p Params, from, to float64, sweep Sweep) (signal.Discrete, error) {
+	if from < 0 || to < 0 {
+		return nil, fmt.Errorf("invalid chirp frequencies: from[%f] to[%f]", from, to)
+	}
+
+	T := p.Duration
+	var phase func(t float64) float64 // in cycles
+
+	switch sweep {
+	case LinearSweep:
+		phase = func(t float64) float64 {
+			return from*t + (to-from)*t*t/(2*T)
+		}
+	case LogSweep:
+		if from == 0 || to == 0 {
+			return nil, fmt.Errorf("logarithmic chirp needs nonzero frequencies: from[%f] to[%f]", from, to)
+		}
+		k := to / from
+		if k == 1 {
+			phase = func(t float64) float64 { return from * t }
+			break
+		}
+		phase = func(t float64) float64 {
+			return from * T * (math.Pow(k, t/T) - 1) / math.Log(k)
+		}
+	default:
+		return nil, fmt.Errorf("unknown sweep: %d", sweep)
+	}
+
+	return sample(p, func(t float64) float64 {
+		return p.Amplitude * math.Sin(2*math.Pi*phase(t)+p.Phase)
+	})
+}
+
+// DTMF generates the dual-tone multi-frequency signal of the key
+// (0-9, A-D, * or #), used in telephony. Each of the two tones has
+// half of the amplitude.
+func DTMF(p Params, key rune) (signal.Discrete, error) {
+	pos, ok := dtmfKeys[key]
+	if !ok {
+		return nil, fmt.Errorf("invalid DTMF key: %q", key)
+	}
+
+	low, high := dtmfRows[pos[0]], dtmfCols[pos[1]]
+	return sample(p, func(t float64) float64 {
+		return p.Amplitude / 2 * (math.Sin(2*math.Pi*low*t+p.Phase) +
+			math.Sin(2*math.Pi*high*t+p.Phase))
+	})
+}
+
+// WhiteNoise generates uniform white noise between -Amplitude and
+// +Amplitude, with a flat spectrum. The same seed gives the same
+// noise.
+func WhiteNoise(p Params, seed int64) (signal.Discrete, error) {
+	n, err := p.samples()
+	if err != nil {
+		return nil, err
+	}
+
+	rnd := rand.New(rand.NewSource(seed))
+	sig := make(signal.Discrete, n)
+	for i := range sig {
+		sig[i] = p.Amplitude * (2*rnd.Float64() - 1)
+	}
+	return sig, nil
+}
+
+// PinkNoise generates pink (1/f) noise, whose power falls 3dB per
+// octave, by filtering white noise (Paul Kellet's refined method).
+// It's scaled to the peak Amplitude.
+func PinkNoise(p Params, seed int64) (signal.Discrete, error) {
+	white, err := WhiteNoise(Params{
+		SampleRate: p.SampleRate,
+		Duration:   p.Duration,
+		Amplitude:  1,
+	}, seed)
+	if err != nil {
+		return nil, err
+	}
+
+	var b0, b1, b2, b3, b4, b5, b6 float64
+	pink := make(signal.Discrete, len(white))
+	for i, w := range white {
+		b0 = 0.99886*b0 + w*0.0555179
+		b1 = 0.99332*b1 + w*0.0750759
+		b2 = 0.96900*b2 + w*0.1538520
+		b3 = 0.86650*b3 + w*0.3104856
+		b4 = 0.55000*b4 + w*0.5329522
+		b5 = -0.7616*b5 - w*0.0168980
+		pink[i] = b0 + b1 + b2 + b3 + b4 + b5 + b6 + w*0.5362
+		b6 = w * 0.115926
+	}
+	return normalize(pink, p.Amplitude), nil
+}
+
+// BrownNoise generates brown (1/f²) noise, whose power falls 6dB per
+// octave, integrating white noise with a leaky integrator that keeps
+// it from drifting away. It's scaled to the peak Amplitude.
+func BrownNoise(p Params, seed int64) (signal.Discrete, error) {
+	white, err := WhiteNoise(Params{
+		SampleRate: p.SampleRate,
+		Duration:   p.Duration,
+		Amplitude:  1,
+	}, seed)
+	if err != nil {
+		return nil, err
+	}
+
+	var last float64
+	brown := make(signal.Discrete, len(white))
+	for i, w := range white {
+		last = 0.998*last + 0.02*w
+		brown[i] = last
+	}
+	return normalize(brown, p.Amplitude), nil
+}
+
+// Impulse generates a unit impulse (delta function) scaled by
+// Amplitude, at delay seconds. Every other sample is zero.
+func Impulse(p Params, delay float64) (signal.Discrete, error) {
+	sig, err := zeros(p)
+	if err != nil {
+		return nil, err
+	}
+
+	i, err := p.index(delay)
+	if err != nil {
+		return nil, err
+	}
+	if i < len(sig) {
+		sig[i] = p.Amplitude
+	}
+	return sig, nil
+}
+
+// Step generates a unit step scaled by Amplitude, that is zero before
+// delay seconds and Amplitude after.
+func Step(p Params, delay float64) (signal.Discrete, error) {
+	sig, err := zeros(p)
+	if err != nil {
+		return nil, err
+	}
+
+	i, err := p.index(delay)
+	if err != nil {
+		return nil, err
+	}
+	for ; i < len(sig); i++ {
+		sig[i] = p.Amplitude
+	}
+	return sig, nil
+}
+
+// samples returns the number of samples of the generated signals.
+func (p Params) samples() (int, error) {
+	if p.SampleRate <= 0 {
+		return 0, fmt.Errorf("invalid sample rate: %f", p.SampleRate)
+	}
+	if p.Duration < 0 {
+		return 0, fmt.Errorf("invalid duration: %f", p.Duration)
+	}
+	return int(math.Floor(p.Duration*p.SampleRate + 0.5)), nil
+}
+
+// index returns the index of the sample at t seconds.
+func (p Params) index(t float64) (int, error) {
+	if t < 0 {
+		return 0, fmt.Errorf("invalid delay: %f", t)
+	}
+	return int(math.Floor(t*p.SampleRate + 0.5)), nil
+}
+
+// sample samples f from zero to Duration.
+func sample(p Params, f signal.Continuous) (signal.Discrete, error) {
+	n, err := p.samples()
+	if err != nil {
+		return nil, err
+	}
+	return signal.SampleN(f, p.SampleRate, 0, n)
+}
+
+// periodic samples the periodic waveform of freq hertz, defined by
+// the shape of one cycle, with the position in the cycle from 0 to 1.
+func periodic(p Params, freq float64, shape func(x float64) float64) (signal.Discrete, error) {
+	if freq < 0 {
+		return nil, fmt.Errorf("invalid frequency: %f", freq)
+	}
+
+	offset := p.Phase / (2 * math.Pi)
+	return sample(p, func(t float64) float64 {
+		return p.Amplitude * shape(frac(freq*t+offset))
+	})
+}
+
+func zeros(p Params) (signal.Discrete, error) {
+	n, err := p.samples()
+	if err != nil {
+		return nil, err
+	}
+	return make(signal.Discrete, n), nil
+}
+
+// frac returns the fractional part of x, in [0, 1).
+func frac(x float64) float64 {
+	return x - math.Floor(x)
+}
+
+// normalize scales sig in place such that its peak is amplitude.
+func normalize(sig signal.Discrete, amplitude float64) signal.Discrete {
+	var peak float64
+	for _, v := range sig {
+		peak = math.Max(peak, math.Abs(v))
+	}
+	if peak == 0 {
+		return sig
+	}
+	for i := range sig {
+		sig[i] *= amplitude / peak
+	}
+	return sig
+}
@@ -0,0 +1,262 @@
+package generator_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/NeowayLabs/signal"
+	"github.com/NeowayLabs/signal/generator"
+)
+
+const precision = 1e-9
+
+var params = generator.Params{
+	SampleRate: 8000,
+	Duration:   0.5,
+	Amplitude:  0.8,
+}
+
+func assertNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func assertAlmost(t *testing.T, got, want, ε float64, msg string) {
+	t.Helper()
+	if !signal.Almost(got, want, ε) {
+		t.Fatalf("%s: %.9f != %.9f", msg, got, want)
+	}
+}
+
+// peakFrequency returns the frequency of the strongest bin of sig.
+func peakFrequency(sig signal.Discrete, samplerate float64) float64 {
+	mag := signal.Magnitude(signal.RealFFT(sig))
+	bins := signal.FrequencyBins(len(sig), samplerate)
+
+	peak := 1
+	for i := range mag[1:] {
+		if mag[i+1] > mag[peak] {
+			peak = i + 1
+		}
+	}
+	return bins[peak]
+}
+
+func TestPeriodic(t *testing.T) {
+	const freq = 100.0 // 80 samples per cycle
+
+	for _, tc := range []struct {
+		name     string
+		generate func(generator.Params) (signal.Discrete, error)
+		values   map[int]float64 // sample index -> value / amplitude
+	}{
+		{
+			name: "sine",
+			generate: func(p generator.Params) (signal.Discrete, error) {
+				return generator.Sine(p, freq)
+			},
+			values: map[int]float64{0: 0, 20: 1, 40: 0, 60: -1},
+		},
+		{
+			name: "square",
+			generate: func(p generator.Params) (signal.Discrete, error) {
+				return generator.Square(p, freq)
+			},
+			values: map[int]float64{0: 1, 39: 1, 40: -1, 79: -1, 80: 1},
+		},
+		{
+			name: "sawtooth",
+			generate: func(p generator.Params) (signal.Discrete, error) {
+				return generator.Sawtooth(p, freq)
+			},
+			values: map[int]float64{0: 0, 20: 0.5, 40: -1, 60: -0.5},
+		},
+		{
+			name: "triangle",
+			generate: func(p generator.Params) (signal.Discrete, error) {
+				return generator.Triangle(p, freq)
+			},
+			values: map[int]float64{0: 0, 10: 0.5, 20: 1, 40: 0, 60: -1},
+		},
+		{
+			name: "pulse",
+			generate: func(p generator.Params) (signal.Discrete, error) {
+				return generator.Pulse(p, freq, 0.25)
+			},
+			values: map[int]float64{0: 1, 19: 1, 20: 0, 79: 0, 80: 1},
+		},
+	} {
+		sig, err := tc.generate(params)
+		assertNoError(t, err)
+
+		if len(sig) != 4000 {
+			t.Fatalf("%s: unexpected length: %d", tc.name, len(sig))
+		}
+		for i, v := range tc.values {
+			assertAlmost(t, sig[i], v*params.Amplitude, precision,
+				tc.name+": sample")
+		}
+		assertAlmost(t, peakFrequency(sig, params.SampleRate), freq, 2,
+			tc.name+": fundamental")
+	}
+}
+
+func TestPhase(t *testing.T) {
+	p := params
+	p.Phase = math.Pi / 2
+
+	sine, err := generator.Sine(p, 100)
+	assertNoError(t, err)
+	assertAlmost(t, sine[0], p.Amplitude, precision, "cosine start")
+
+	square, err := generator.Square(p, 100)
+	assertNoError(t, err)
+	assertAlmost(t, square[19], p.Amplitude, precision, "square before the edge")
+	assertAlmost(t, square[21], -p.Amplitude, precision, "square after the edge")
+}
+
+func TestChirp(t *testing.T) {
+	p := params
+	p.Duration = 1
+
+	for _, sweep := range []generator.Sweep{generator.LinearSweep, generator.LogSweep} {
+		sig, err := generator.Chirp(p, 100, 2000, sweep)
+		assertNoError(t, err)
+
+		// the frequency at the start and at the end of the chirp
+		start := peakFrequency(sig[:800], p.SampleRate)
+		end := peakFrequency(sig[len(sig)-800:], p.SampleRate)
+		if start > 400 || end < 1400 {
+			t.Fatalf("sweep %d: unexpected frequencies: start[%f] end[%f]",
+				sweep, start, end)
+		}
+	}
+
+	// constant frequency chirps are sine waves
+	sine, err := generator.Sine(p, 440)
+	assertNoError(t, err)
+	for _, sweep := range []generator.Sweep{generator.LinearSweep, generator.LogSweep} {
+		sig, err := generator.Chirp(p, 440, 440, sweep)
+		assertNoError(t, err)
+		for i := range sig {
+			assertAlmost(t, sig[i], sine[i], 1e-6, "constant chirp")
+		}
+	}
+
+	_, err = generator.Chirp(p, 0, 1000, generator.LogSweep)
+	if err == nil {
+		t.Fatal("logarithmic chirp from zero must fail")
+	}
+}
+
+func TestDTMF(t *testing.T) {
+	sig, err := generator.DTMF(params, '5')
+	assertNoError(t, err)
+
+	// 770Hz + 1336Hz
+	mag := signal.Magnitude(signal.RealFFT(sig))
+	bins := signal.FrequencyBins(len(sig), params.SampleRate)
+	for i, f := range bins {
+		strong := mag[i] > float64(len(sig))*params.Amplitude/8
+		if strong != (f == 770 || f == 1336) {
+			t.Fatalf("unexpected magnitude at %fHz: %f", f, mag[i])
+		}
+	}
+
+	_, err = generator.DTMF(params, 'x')
+	if err == nil {
+		t.Fatal("invalid key must fail")
+	}
+}
+
+func TestNoise(t *testing.T) {
+	p := params
+	p.Duration = 2
+
+	for _, tc := range []struct {
+		name     string
+		generate func(generator.Params, int64) (signal.Discrete, error)
+		slope    float64 // spectral slope, in dB per octave
+	}{
+		{"white", generator.WhiteNoise, 0},
+		{"pink", generator.PinkNoise, -3},
+		{"brown", generator.BrownNoise, -6},
+	} {
+		sig, err := tc.generate(p, 42)
+		assertNoError(t, err)
+		again, err := tc.generate(p, 42)
+		assertNoError(t, err)
+
+		var peak float64
+		for i, v := range sig {
+			if v != again[i] {
+				t.Fatalf("%s: the same seed must give the same noise", tc.name)
+			}
+			peak = math.Max(peak, math.Abs(v))
+		}
+		if peak > p.Amplitude+precision || peak < p.Amplitude/2 {
+			t.Fatalf("%s: unexpected peak: %f", tc.name, peak)
+		}
+
+		// power of the octaves 250-500Hz and 1000-2000Hz
+		mag := signal.Magnitude(signal.RealFFT(sig))
+		bins := signal.FrequencyBins(len(sig), p.SampleRate)
+		power := func(from, to float64) float64 {
+			var sum float64
+			var n int
+			for i, f := range bins {
+				if f >= from && f < to {
+					sum += mag[i] * mag[i]
+					n++
+				}
+			}
+			return sum / float64(n)
+		}
+		slope := 10 * math.Log10(power(1000, 2000)/power(250, 500)) / 2
+		assertAlmost(t, slope, tc.slope, 1, tc.name+": spectral slope")
+	}
+}
+
+func TestImpulseAndStep(t *testing.T) {
+	impulse, err := generator.Impulse(params, 0.001)
+	assertNoError(t, err)
+	step, err := generator.Step(params, 0.001)
+	assertNoError(t, err)
+
+	for i := range impulse {
+		var want float64
+		if i == 8 {
+			want = params.Amplitude
+		}
+		assertAlmost(t, impulse[i], want, 0, "impulse")
+
+		want = 0
+		if i >= 8 {
+			want = params.Amplitude
+		}
+		assertAlmost(t, step[i], want, 0, "step")
+	}
+}
+
+func TestInvalidParams(t *testing.T) {
+	for _, p := range []generator.Params{
+		{SampleRate: 0, Duration: 1},
+		{SampleRate: 8000, Duration: -1},
+	} {
+		_, err := generator.Sine(p, 440)
+		if err == nil {
+			t.Fatalf("params %+v must fail", p)
+		}
+	}
+
+	_, err := generator.Sine(params, -1)
+	if err == nil {
+		t.Fatal("negative frequency must fail")
+	}
+	_, err = generator.Pulse(params, 100, 1.5)
+	if err == nil {
+		t.Fatal("invalid duty cycle must fail")
+	}
+}
@@ -1,6 +1,7 @@
 package signal
 
 import (
+	"fmt"
 	"math"
 )
 
@@ -64,3 +65,193 @@ func HMean(hist Histogram) float64 {
 	}
 	return sum / float64(n)
 }
+
+// Binned is a histogram that counts how many samples fall in each of
+// the bins of same width covering the range [Min, Max] (DSP Book,
+// Chapter 2). Samples outside the range are counted apart, in the
+// Underflow and Overflow counters, and are ignored by the statistics.
+type Binned struct {
+	Min, Max  float64
+	Counts    []uint64
+	Underflow uint64 // samples below Min
+	Overflow  uint64 // samples above Max
+}
+
+// NewBinned creates an empty histogram with nbins bins over the range
+// [min, max].
+func NewBinned(min, max float64, nbins int) (*Binned, error) {
+	if nbins <= 0 {
+		return nil, fmt.Errorf("invalid number of bins: %d", nbins)
+	}
+	if !(min < max) {
+		return nil, fmt.Errorf("invalid range: [%f, %f]", min, max)
+	}
+
+	return &Binned{
+		Min:    min,
+		Max:    max,
+		Counts: make([]uint64, nbins),
+	}, nil
+}
+
+// NewBinnedWidth creates an empty histogram with bins of the given
+// width starting at min. The range is extended up to a whole number
+// of bins covering max.
+func NewBinnedWidth(min, max, width float64) (*Binned, error) {
+	if width <= 0 {
+		return nil, fmt.Errorf("invalid bin width: %f", width)
+	}
+	if !(min < max) {
+		return nil, fmt.Errorf("invalid range: [%f, %f]", min, max)
+	}
+
+	nbins := int(math.Ceil((max - min) / width))
+	return NewBinned(min, min+float64(nbins)*width, nbins)
+}
+
+// Width returns the width of the bins.
+func (h *Binned) Width() float64 {
+	return (h.Max - h.Min) / float64(len(h.Counts))
+}
+
+// Bin returns the index of the bin of the value x, or false if x is
+// outside of the range of the histogram.
+func (h *Binned) Bin(x float64) (int, bool) {
+	if x < h.Min || x > h.Max || math.IsNaN(x) {
+		return 0, false
+	}
+
+	i := int((x - h.Min) / h.Width())
+	if i >= len(h.Counts) {
+		// x == Max belongs to the last bin
+		i = len(h.Counts) - 1
+	}
+	return i, true
+}
+
+// Center returns the value at the center of bin i.
+func (h *Binned) Center(i int) float64 {
+	return h.Min + (float64(i)+0.5)*h.Width()
+}
+
+// Add counts the sample x.
+func (h *Binned) Add(x float64) {
+	if i, ok := h.Bin(x); ok {
+		h.Counts[i]++
+		return
+	}
+
+	if x < h.Min {
+		h.Underflow++
+	} else {
+		h.Overflow++
+	}
+}
+
+// AddSignal counts every sample of s.
+func (h *Binned) AddSignal(s Discrete) {
+	for _, v := range s {
+		h.Add(v)
+	}
+}
+
+// Total returns the number of samples inside the range.
+func (h *Binned) Total() uint64 {
+	var total uint64
+	for _, c := range h.Counts {
+		total += c
+	}
+	return total
+}
+
+// PDF returns the probability density of each bin, ie. the fraction
+// of the samples in the bin divided by the bin width, such that the
+// PDF integrates to 1.
+func (h *Binned) PDF() Discrete {
+	pdf := make(Discrete, len(h.Counts))
+	total := h.Total()
+	if total == 0 {
+		return pdf
+	}
+
+	norm := float64(total) * h.Width()
+	for i, c := range h.Counts {
+		pdf[i] = float64(c) / norm
+	}
+	return pdf
+}
+
+// CDF returns the cumulative distribution at the upper edge of each
+// bin, ie. the fraction of the samples below the end of the bin.
+func (h *Binned) CDF() Discrete {
+	cdf := make(Discrete, len(h.Counts))
+	total := h.Total()
+	if total == 0 {
+		return cdf
+	}
+
+	var sum uint64
+	for i, c := range h.Counts {
+		sum += c
+		cdf[i] = float64(sum) / float64(total)
+	}
+	return cdf
+}
+
+// Percentile returns the value below which p percent of the samples
+// fall, interpolating linearly inside the bins.
+func (h *Binned) Percentile(p float64) float64 {
+	total := h.Total()
+	if total == 0 {
+		return math.NaN()
+	}
+
+	p = math.Max(0, math.Min(100, p))
+	target := p / 100 * float64(total)
+
+	var sum float64
+	for i, c := range h.Counts {
+		if c == 0 {
+			continue
+		}
+		next := sum + float64(c)
+		if next >= target {
+			frac := (target - sum) / float64(c)
+			return h.Min + (float64(i)+frac)*h.Width()
+		}
+		sum = next
+	}
+	return h.Max
+}
+
+// Mean calculates the mean from the histogram, using the center of
+// the bins (DSP Book, Chapter 2, Eq. 2-6).
+func (h *Binned) Mean() float64 {
+	total := h.Total()
+	if total == 0 {
+		return 0
+	}
+
+	var sum float64
+	for i, c := range h.Counts {
+		sum += h.Center(i) * float64(c)
+	}
+	return sum / float64(total)
+}
+
+// StdDeviation calculates the standard deviation from the histogram,
+// using the center of the bins (DSP Book, Chapter 2, Eq. 2-7).
+func (h *Binned) StdDeviation() float64 {
+	total := h.Total()
+	if total < 2 {
+		return 0
+	}
+
+	μ := h.Mean()
+	var sum float64
+	for i, c := range h.Counts {
+		d := h.Center(i) - μ
+		sum += d * d * float64(c)
+	}
+	return math.Sqrt(sum / float64(total-1))
+}
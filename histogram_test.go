@@ -0,0 +1,73 @@
+package signal_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/NeowayLabs/signal"
+)
+
+func TestBinned(t *testing.T) {
+	h, err := signal.NewBinned(0, 10, 5)
+	assert(t, err == nil, fmt("new binned: %v", err))
+
+	h.AddSignal(signal.Discrete{-1, 0, 1, 2.5, 3, 5, 9.9, 10, 11, 12})
+
+	assert(t, h.Underflow == 1, fmt("underflow: %d", h.Underflow))
+	assert(t, h.Overflow == 2, fmt("overflow: %d", h.Overflow))
+	assert(t, h.Total() == 7, fmt("total: %d", h.Total()))
+
+	expected := []uint64{2, 2, 1, 0, 2}
+	for i, c := range expected {
+		assert(t, h.Counts[i] == c, fmt("bin[%d]: %d != %d", i, h.Counts[i], c))
+	}
+
+	assertAlmost(t, h.Width(), 2, precision, "width")
+	assertAlmost(t, h.Center(0), 1, precision, "center")
+
+	pdf := h.PDF()
+	var integral float64
+	for _, v := range pdf {
+		integral += v * h.Width()
+	}
+	assertAlmost(t, integral, 1, precision, "pdf integral")
+
+	cdf := h.CDF()
+	assertAlmost(t, cdf[0], 2.0/7, precision, "cdf first bin")
+	assertAlmost(t, cdf[len(cdf)-1], 1, precision, "cdf last bin")
+}
+
+func TestBinnedWidth(t *testing.T) {
+	h, err := signal.NewBinnedWidth(-1, 1, 0.3)
+	assert(t, err == nil, fmt("new binned: %v", err))
+	assert(t, len(h.Counts) == 7, fmt("number of bins: %d", len(h.Counts)))
+	assertAlmost(t, h.Max, 1.1, precision, "extended max")
+
+	_, err = signal.NewBinnedWidth(0, 1, 0)
+	assert(t, err != nil, "zero width must fail")
+	_, err = signal.NewBinned(1, 0, 10)
+	assert(t, err != nil, "invalid range must fail")
+}
+
+func TestBinnedStatistics(t *testing.T) {
+	const n = 100000
+
+	// uniform distribution over [0, 1)
+	sig := make(signal.Discrete, n)
+	for i := range sig {
+		sig[i] = float64(i) / n
+	}
+
+	h, err := signal.NewBinned(0, 1, 100)
+	assert(t, err == nil, fmt("new binned: %v", err))
+	h.AddSignal(sig)
+
+	assertAlmost(t, h.Mean(), signal.Mean(sig), 1e-3, "histogram mean")
+	assertAlmost(t, h.StdDeviation(), signal.StdDeviation(sig), 1e-3, "histogram std deviation")
+	assertAlmost(t, h.StdDeviation(), 1/math.Sqrt(12), 1e-3, "uniform std deviation")
+
+	assertAlmost(t, h.Percentile(50), 0.5, 1e-4, "median")
+	assertAlmost(t, h.Percentile(90), 0.9, 1e-4, "90th percentile")
+	assertAlmost(t, h.Percentile(0), 0, 1e-4, "0th percentile")
+	assertAlmost(t, h.Percentile(100), 1, 1e-4, "100th percentile")
+}
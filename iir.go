@@ -0,0 +1,253 @@
+package signal
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+)
+
+type (
+	// Biquad is a second order recursive section with coefficients
+	// normalized by a0:
+	// y(n) = b0·x(n) + b1·x(n-1) + b2·x(n-2) - a1·y(n-1) - a2·y(n-2)
+	// First order sections have B2 and A2 equal to zero.
+	Biquad struct {
+		B0, B1, B2 float64 // feedforward (zeros)
+		A1, A2     float64 // feedback (poles)
+	}
+
+	// IIR is an infinite impulse response (recursive) filter,
+	// implemented as a cascade of biquad sections (DSP Book, Chapters
+	// 19 and 20). Each section keeps its own state, using the
+	// transposed direct form II.
+	IIR struct {
+		Gain     float64
+		Sections []Biquad
+
+		state [][2]float64
+	}
+)
+
+// NewIIR creates a new IIR filter with the cascade of sections,
+// scaling the output by gain.
+func NewIIR(gain float64, sections ...Biquad) *IIR {
+	f := &IIR{
+		Gain:     gain,
+		Sections: sections,
+	}
+	f.Reset()
+	return f
+}
+
+// Process filters the block, returning the output with the same
+// length of the block.
+func (f *IIR) Process(block Discrete) Discrete {
+	if len(f.state) != len(f.Sections) {
+		f.Reset()
+	}
+
+	out := make(Discrete, len(block))
+	for i, x := range block {
+		v := x * f.Gain
+		for j, s := range f.Sections {
+			st := &f.state[j]
+			y := s.B0*v + st[0]
+			st[0] = s.B1*v - s.A1*y + st[1]
+			st[1] = s.B2*v - s.A2*y
+			v = y
+		}
+		out[i] = v
+	}
+	return out
+}
+
+// Reset clears the state of every section.
+func (f *IIR) Reset() {
+	f.state = make([][2]float64, len(f.Sections))
+}
+
+// Response returns the complex frequency response of the filter at
+// freq hertz.
+func (f *IIR) Response(freq, samplerate float64) complex128 {
+	z1 := cmplx.Rect(1, -2*math.Pi*freq/samplerate) // z⁻¹
+	z2 := z1 * z1
+
+	h := complex(f.Gain, 0)
+	for _, s := range f.Sections {
+		num := complex(s.B0, 0) + complex(s.B1, 0)*z1 + complex(s.B2, 0)*z2
+		den := 1 + complex(s.A1, 0)*z1 + complex(s.A2, 0)*z2
+		h *= num / den
+	}
+	return h
+}
+
+// SinglePoleLowPass creates a single pole low-pass filter, that
+// mimics a RC network (DSP Book, Chapter 19).
+// y(n) = (1-x)·x(n) + x·y(n-1), where x = e^(-2π·cutoff/samplerate)
+func SinglePoleLowPass(cutoff, samplerate float64) (*IIR, error) {
+	fc, err := normalizedCutoff(cutoff, samplerate)
+	if err != nil {
+		return nil, err
+	}
+
+	x := math.Exp(-2 * math.Pi * fc)
+	return NewIIR(1, Biquad{B0: 1 - x, A1: -x}), nil
+}
+
+// SinglePoleHighPass creates a single pole high-pass filter
+// (DSP Book, Chapter 19).
+func SinglePoleHighPass(cutoff, samplerate float64) (*IIR, error) {
+	fc, err := normalizedCutoff(cutoff, samplerate)
+	if err != nil {
+		return nil, err
+	}
+
+	x := math.Exp(-2 * math.Pi * fc)
+	return NewIIR(1, Biquad{B0: (1 + x) / 2, B1: -(1 + x) / 2, A1: -x}), nil
+}
+
+// ButterworthLowPass designs a low-pass Butterworth filter (maximally
+// flat passband) of the given order, with -3dB at cutoff hertz.
+func ButterworthLowPass(order int, cutoff, samplerate float64) (*IIR, error) {
+	return designIIR(order, cutoff, samplerate, butterworthPoles(order), false, 1)
+}
+
+// ButterworthHighPass designs a high-pass Butterworth filter of the
+// given order, with -3dB at cutoff hertz.
+func ButterworthHighPass(order int, cutoff, samplerate float64) (*IIR, error) {
+	return designIIR(order, cutoff, samplerate, butterworthPoles(order), true, 1)
+}
+
+// ChebyshevLowPass designs a low-pass Chebyshev (type I) filter of the
+// given order, with ripple dB of passband ripple (DSP Book, Chapter
+// 20). The cutoff is the edge of the passband, where the response
+// leaves the ripple band.
+func ChebyshevLowPass(order int, ripple, cutoff, samplerate float64) (*IIR, error) {
+	poles, gain, err := chebyshevPoles(order, ripple)
+	if err != nil {
+		return nil, err
+	}
+	return designIIR(order, cutoff, samplerate, poles, false, gain)
+}
+
+// ChebyshevHighPass designs a high-pass Chebyshev (type I) filter of
+// the given order, with ripple dB of passband ripple.
+func ChebyshevHighPass(order int, ripple, cutoff, samplerate float64) (*IIR, error) {
+	poles, gain, err := chebyshevPoles(order, ripple)
+	if err != nil {
+		return nil, err
+	}
+	return designIIR(order, cutoff, samplerate, poles, true, gain)
+}
+
+// butterworthPoles returns the poles of the analog Butterworth
+// prototype with cutoff at 1 rad/s, that lie in the left half of the
+// unit circle. Only one pole of each conjugate pair is returned
+// (with positive imaginary part), plus the real pole of odd orders.
+func butterworthPoles(order int) []complex128 {
+	var poles []complex128
+	for k := 0; k < (order+1)/2; k++ {
+		θ := math.Pi * float64(2*k+1) / float64(2*order)
+		poles = append(poles, complex(-math.Sin(θ), math.Cos(θ)))
+	}
+	return poles
+}
+
+// chebyshevPoles returns the poles of the analog Chebyshev prototype
+// with passband edge at 1 rad/s, in the same layout of
+// butterworthPoles, and the gain that puts the passband in the
+// ripple band.
+func chebyshevPoles(order int, ripple float64) ([]complex128, float64, error) {
+	if ripple <= 0 {
+		return nil, 0, fmt.Errorf("invalid ripple: %f dB", ripple)
+	}
+	if order < 1 {
+		return nil, 0, fmt.Errorf("invalid filter order: %d", order)
+	}
+
+	ε := math.Sqrt(math.Pow(10, ripple/10) - 1)
+	v0 := math.Asinh(1/ε) / float64(order)
+
+	var poles []complex128
+	for k := 0; k < (order+1)/2; k++ {
+		θ := math.Pi * float64(2*k+1) / float64(2*order)
+		poles = append(poles, complex(-math.Sinh(v0)*math.Sin(θ), math.Cosh(v0)*math.Cos(θ)))
+	}
+
+	gain := 1.0
+	if order%2 == 0 {
+		// even orders start the passband at the bottom of the ripple
+		gain = 1 / math.Sqrt(1+ε*ε)
+	}
+	return poles, gain, nil
+}
+
+// designIIR converts the analog prototype poles into digital biquads
+// using the bilinear transform, with the cutoff prewarped.
+func designIIR(order int, cutoff, samplerate float64, poles []complex128, highpass bool, gain float64) (*IIR, error) {
+	if order < 1 {
+		return nil, fmt.Errorf("invalid filter order: %d", order)
+	}
+	fc, err := normalizedCutoff(cutoff, samplerate)
+	if err != nil {
+		return nil, err
+	}
+
+	k := math.Tan(math.Pi * fc)
+
+	var sections []Biquad
+	for _, p := range poles {
+		if imag(p) < 1e-12 {
+			sections = append(sections, firstOrderSection(real(p), k, highpass))
+			continue
+		}
+		sections = append(sections, secondOrderSection(p, k, highpass))
+	}
+	return NewIIR(gain, sections...), nil
+}
+
+// secondOrderSection transforms the analog section with the poles p
+// and its conjugate, and unity gain in the passband. The low-pass
+// section is |p|²/(s² - 2Re(p)s + |p|²) and the high-pass section is
+// s²/(s² - 2Re(p)/|p|²·s + 1/|p|²).
+func secondOrderSection(p complex128, k float64, highpass bool) Biquad {
+	a := -2 * real(p)
+	b := real(p)*real(p) + imag(p)*imag(p)
+
+	// numerator and denominator: n2·s² + n1·s + n0
+	n2, n1, n0 := 0.0, 0.0, b
+	d1, d0 := a, b
+	if highpass {
+		n2, n0 = 1, 0
+		d1, d0 = a/b, 1/b
+	}
+
+	// s = (1/k)·(1-z⁻¹)/(1+z⁻¹)
+	norm := 1 + d1*k + d0*k*k
+	return Biquad{
+		B0: (n2 + n1*k + n0*k*k) / norm,
+		B1: (-2*n2 + 2*n0*k*k) / norm,
+		B2: (n2 - n1*k + n0*k*k) / norm,
+		A1: (-2 + 2*d0*k*k) / norm,
+		A2: (1 - d1*k + d0*k*k) / norm,
+	}
+}
+
+// firstOrderSection transforms the analog section with the real pole
+// p and unity gain in the passband. The low-pass section is -p/(s - p)
+// and the high-pass section is s/(s - 1/p).
+func firstOrderSection(p, k float64, highpass bool) Biquad {
+	n1, n0 := 0.0, -p
+	d0 := -p
+	if highpass {
+		n1, n0 = 1, 0
+		d0 = -1 / p
+	}
+
+	norm := 1 + d0*k
+	return Biquad{
+		B0: (n1 + n0*k) / norm,
+		B1: (-n1 + n0*k) / norm,
+		A1: (-1 + d0*k) / norm,
+	}
+}
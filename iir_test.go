@@ -0,0 +1,112 @@
+package signal_test
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/NeowayLabs/signal"
+)
+
+const rate = 8000.0
+
+func magnitude(f *signal.IIR, freq float64) float64 {
+	return cmplx.Abs(f.Response(freq, rate))
+}
+
+func TestSinglePole(t *testing.T) {
+	lp, err := signal.SinglePoleLowPass(100, rate)
+	assert(t, err == nil, fmt("low-pass: %v", err))
+	assertAlmost(t, magnitude(lp, 0), 1, 1e-9, "low-pass DC gain")
+	assert(t, magnitude(lp, 3000) < 0.1, "low-pass attenuation")
+
+	hp, err := signal.SinglePoleHighPass(100, rate)
+	assert(t, err == nil, fmt("high-pass: %v", err))
+	assertAlmost(t, magnitude(hp, 0), 0, 1e-9, "high-pass DC gain")
+	assertAlmost(t, magnitude(hp, rate/2), 1, 1e-9, "high-pass nyquist gain")
+
+	// step response of the low-pass converges to 1
+	step := make(signal.Discrete, 2000)
+	for i := range step {
+		step[i] = 1
+	}
+	out := lp.Process(step)
+	assertAlmost(t, out[len(out)-1], 1, 1e-6, "low-pass step response")
+}
+
+func TestButterworth(t *testing.T) {
+	for _, order := range []int{1, 2, 4, 5} {
+		lp, err := signal.ButterworthLowPass(order, 1000, rate)
+		assert(t, err == nil, fmt("low-pass: %v", err))
+		assert(t, len(lp.Sections) == (order+1)/2, "number of sections")
+		assertAlmost(t, magnitude(lp, 0), 1, 1e-9, fmt("order %d: low-pass DC gain", order))
+		assertAlmost(t, magnitude(lp, 1000), 1/math.Sqrt2, 1e-9,
+			fmt("order %d: low-pass cutoff", order))
+
+		hp, err := signal.ButterworthHighPass(order, 1000, rate)
+		assert(t, err == nil, fmt("high-pass: %v", err))
+		assertAlmost(t, magnitude(hp, rate/2), 1, 1e-9, fmt("order %d: high-pass gain", order))
+		assertAlmost(t, magnitude(hp, 1000), 1/math.Sqrt2, 1e-9,
+			fmt("order %d: high-pass cutoff", order))
+		assertAlmost(t, magnitude(hp, 0), 0, 1e-9, fmt("order %d: high-pass DC", order))
+	}
+
+	lp, _ := signal.ButterworthLowPass(6, 1000, rate)
+	assert(t, magnitude(lp, 3000) < 1e-3, "6th order attenuation")
+}
+
+func TestChebyshev(t *testing.T) {
+	const ripple = 1.0
+	bottom := math.Pow(10, -ripple/20)
+
+	for _, order := range []int{2, 3, 4, 5} {
+		lp, err := signal.ChebyshevLowPass(order, ripple, 1000, rate)
+		assert(t, err == nil, fmt("low-pass: %v", err))
+		assertAlmost(t, magnitude(lp, 1000), bottom, 1e-9,
+			fmt("order %d: low-pass passband edge", order))
+		for freq := 0.0; freq < 1000; freq += 10 {
+			m := magnitude(lp, freq)
+			assert(t, m <= 1+1e-9 && m >= bottom-1e-9,
+				fmt("order %d: low-pass ripple at %f: %f", order, freq, m))
+		}
+
+		hp, err := signal.ChebyshevHighPass(order, ripple, 1000, rate)
+		assert(t, err == nil, fmt("high-pass: %v", err))
+		assertAlmost(t, magnitude(hp, 1000), bottom, 1e-9,
+			fmt("order %d: high-pass passband edge", order))
+		assert(t, magnitude(hp, 100) < 0.1, fmt("order %d: high-pass attenuation", order))
+	}
+
+	_, err := signal.ChebyshevLowPass(4, 0, 1000, rate)
+	assert(t, err != nil, "zero ripple must fail")
+}
+
+func TestIIRStreaming(t *testing.T) {
+	f, err := signal.ButterworthLowPass(4, 500, rate)
+	assert(t, err == nil, fmt("low-pass: %v", err))
+
+	x := testSignal(1000)
+	expected := f.Process(x)
+
+	f.Reset()
+	var got signal.Discrete
+	for start := 0; start < len(x); start += 93 {
+		end := start + 93
+		if end > len(x) {
+			end = len(x)
+		}
+		got = append(got, f.Process(x[start:end])...)
+	}
+	assertDiscreteAlmost(t, got, expected, 1e-12, "streaming")
+
+	var _ signal.Filter = f
+	var _ signal.Filter = signal.NewFIR(signal.Discrete{1})
+}
+
+func TestIIRInvalid(t *testing.T) {
+	_, err := signal.ButterworthLowPass(0, 1000, rate)
+	assert(t, err != nil, "zero order must fail")
+
+	_, err = signal.ButterworthHighPass(2, 5000, rate)
+	assert(t, err != nil, "cutoff above nyquist must fail")
+}
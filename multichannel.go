@@ -0,0 +1,95 @@
+package signal
+
+import "fmt"
+
+// Multichannel is a set of discrete signals sampled at the same rate,
+// like a stereo audio or the recordings of a microphone array.
+// Each channel has one sample per frame.
+type Multichannel struct {
+	SampleRate float64 // samples per second of each channel
+	Channels   []Discrete
+}
+
+// NewMultichannel creates a zeroed multichannel signal with nframes
+// samples per channel.
+func NewMultichannel(nchannels, nframes int, samplerate float64) Multichannel {
+	m := Multichannel{
+		SampleRate: samplerate,
+		Channels:   make([]Discrete, nchannels),
+	}
+	for i := range m.Channels {
+		m.Channels[i] = make(Discrete, nframes)
+	}
+	return m
+}
+
+// Deinterleave splits the interleaved samples (one sample of each
+// channel per frame) into nchannels channels.
+func Deinterleave(samples Discrete, nchannels int, samplerate float64) (Multichannel, error) {
+	if nchannels <= 0 {
+		return Multichannel{}, fmt.Errorf("invalid number of channels: %d", nchannels)
+	}
+	if len(samples)%nchannels != 0 {
+		return Multichannel{}, fmt.Errorf("%d samples isn't a multiple of %d channels",
+			len(samples), nchannels)
+	}
+
+	m := NewMultichannel(nchannels, len(samples)/nchannels, samplerate)
+	for i, v := range samples {
+		m.Channels[i%nchannels][i/nchannels] = v
+	}
+	return m, nil
+}
+
+// Interleave joins the channels into a single signal with one sample
+// of each channel per frame. Channels are truncated to NumFrames.
+func (m Multichannel) Interleave() Discrete {
+	nchannels := m.NumChannels()
+	nframes := m.NumFrames()
+
+	samples := make(Discrete, nframes*nchannels)
+	for ch, sig := range m.Channels {
+		for i := 0; i < nframes; i++ {
+			samples[i*nchannels+ch] = sig[i]
+		}
+	}
+	return samples
+}
+
+// NumChannels returns the number of channels.
+func (m Multichannel) NumChannels() int {
+	return len(m.Channels)
+}
+
+// NumFrames returns the number of frames, ie. the length of the
+// shortest channel.
+func (m Multichannel) NumFrames() int {
+	if len(m.Channels) == 0 {
+		return 0
+	}
+
+	n := len(m.Channels[0])
+	for _, sig := range m.Channels[1:] {
+		if len(sig) < n {
+			n = len(sig)
+		}
+	}
+	return n
+}
+
+// Frame returns the samples of every channel at frame i.
+func (m Multichannel) Frame(i int) Discrete {
+	frame := make(Discrete, len(m.Channels))
+	for ch, sig := range m.Channels {
+		frame[ch] = sig[i]
+	}
+	return frame
+}
+
+// Duration returns the duration of the signal in seconds.
+func (m Multichannel) Duration() float64 {
+	if m.SampleRate == 0 {
+		return 0
+	}
+	return float64(m.NumFrames()) / m.SampleRate
+}
@@ -0,0 +1,42 @@
+package signal_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/NeowayLabs/signal"
+)
+
+func TestDeinterleave(t *testing.T) {
+	samples := signal.Discrete{1, -1, 2, -2, 3, -3}
+
+	m, err := signal.Deinterleave(samples, 2, 8000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []signal.Discrete{{1, 2, 3}, {-1, -2, -3}}
+	assert(t, reflect.DeepEqual(m.Channels, expected), fmt("channels differs: %v", m.Channels))
+	assert(t, m.NumChannels() == 2, "number of channels")
+	assert(t, m.NumFrames() == 3, "number of frames")
+	assert(t, reflect.DeepEqual(m.Frame(1), signal.Discrete{2, -2}), "frame")
+	assertAlmost(t, m.Duration(), 3.0/8000, precision, "duration")
+
+	assert(t, reflect.DeepEqual(m.Interleave(), samples), "interleave")
+}
+
+func TestDeinterleaveInvalid(t *testing.T) {
+	_, err := signal.Deinterleave(signal.Discrete{1, 2, 3}, 2, 8000)
+	assert(t, err != nil, "expected error for incomplete frame")
+
+	_, err = signal.Deinterleave(signal.Discrete{1, 2}, 0, 8000)
+	assert(t, err != nil, "expected error for zero channels")
+}
+
+func TestInterleaveTruncates(t *testing.T) {
+	m := signal.Multichannel{
+		Channels: []signal.Discrete{{1, 2, 3}, {-1, -2}},
+	}
+	assert(t, reflect.DeepEqual(m.Interleave(), signal.Discrete{1, -1, 2, -2}),
+		"interleave should truncate to the shortest channel")
+}
@@ -0,0 +1,108 @@
+package signal
+
+import (
+	"fmt"
+	"math/cmplx"
+)
+
+// Periodogram estimates the one-sided power spectral density of sig,
+// in units² per hertz, from the spectrum of the whole signal
+// multiplied by the window (nil uses the rectangular window). The
+// density of the bin k is at the frequency k·samplerate/len(sig) (see
+// FrequencyBins), and the integral of the density is the mean power
+// of the windowed signal, corrected by the energy gain of the window.
+func Periodogram(sig Discrete, samplerate float64, window Window) (Discrete, error) {
+	if samplerate <= 0 {
+		return nil, fmt.Errorf("invalid sample rate: %f", samplerate)
+	}
+	if len(sig) == 0 {
+		return nil, fmt.Errorf("empty signal")
+	}
+	if window == nil {
+		window = Rectangular
+	}
+
+	w := window(len(sig))
+	if energy(w) == 0 {
+		return nil, fmt.Errorf("window of %d samples has zero energy", len(w))
+	}
+	return periodogram(sig, samplerate, w), nil
+}
+
+// Bartlett estimates the power spectral density of sig averaging the
+// periodograms of non-overlapping segments of segment samples, that
+// lowers the variance of the estimate by the number of segments, at
+// the cost of the frequency resolution. The trailing samples that
+// don't fill a segment are ignored.
+func Bartlett(sig Discrete, samplerate float64, segment int) (Discrete, error) {
+	return Welch(sig, samplerate, segment, 0, Rectangular)
+}
+
+// Welch estimates the power spectral density of sig averaging the
+// periodograms of windowed segments of segment samples overlapping by
+// overlap samples (nil window uses Hann). A 50% overlap recovers most
+// of the samples attenuated by the window. The density of the bin k
+// is at the frequency k·samplerate/segment.
+func Welch(sig Discrete, samplerate float64, segment, overlap int, window Window) (Discrete, error) {
+	if samplerate <= 0 {
+		return nil, fmt.Errorf("invalid sample rate: %f", samplerate)
+	}
+	if segment <= 0 || segment > len(sig) {
+		return nil, fmt.Errorf("invalid segment size %d for %d samples", segment, len(sig))
+	}
+	if overlap < 0 || overlap >= segment {
+		return nil, fmt.Errorf("invalid overlap: %d", overlap)
+	}
+	if window == nil {
+		window = Hann
+	}
+
+	w := window(segment)
+	if energy(w) == 0 {
+		return nil, fmt.Errorf("window of %d samples has zero energy", segment)
+	}
+	step := segment - overlap
+
+	var psd Discrete
+	nsegments := 0
+	for start := 0; start+segment <= len(sig); start += step {
+		p := periodogram(sig[start:start+segment], samplerate, w)
+		if psd == nil {
+			psd = p
+		} else {
+			for k, v := range p {
+				psd[k] += v
+			}
+		}
+		nsegments++
+	}
+
+	for k := range psd {
+		psd[k] /= float64(nsegments)
+	}
+	return psd, nil
+}
+
+// periodogram calculates the one-sided (positive-frequency) density
+// of the signal windowed by w, that must have non-zero energy. The
+// bins other than DC and Nyquist are doubled to add the power of the
+// negative frequencies.
+func periodogram(sig Discrete, samplerate float64, w Discrete) Discrete {
+	windowed := make(Discrete, len(sig))
+	copy(windowed, sig)
+	ApplyWindow(windowed, w)
+
+	X := RealFFT(windowed)
+	scale := 1 / (samplerate * energy(w))
+
+	n := len(sig)
+	psd := make(Discrete, len(X))
+	for k, v := range X {
+		a := cmplx.Abs(v)
+		psd[k] = a * a * scale
+		if k > 0 && (n%2 == 1 || k < n/2) {
+			psd[k] *= 2
+		}
+	}
+	return psd
+}
@@ -0,0 +1,128 @@
+package signal_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/NeowayLabs/signal"
+)
+
+// power integrates the density.
+func power(psd signal.Discrete, df float64) float64 {
+	var sum float64
+	for _, v := range psd {
+		sum += v * df
+	}
+	return sum
+}
+
+func noise(n int, σ float64) signal.Discrete {
+	rnd := rand.New(rand.NewSource(1))
+	sig := make(signal.Discrete, n)
+	for i := range sig {
+		sig[i] = rnd.NormFloat64() * σ
+	}
+	return sig
+}
+
+func TestPeriodogram(t *testing.T) {
+	const rate = 1000.0
+
+	// Parseval: the integral of the density is the mean power
+	for _, n := range []int{256, 255} {
+		sig := testSignal(n)
+		psd, err := signal.Periodogram(sig, rate, nil)
+		assert(t, err == nil, fmt("periodogram: %v", err))
+		assert(t, len(psd) == n/2+1, fmt("number of bins: %d", len(psd)))
+
+		var meanPower float64
+		for _, v := range sig {
+			meanPower += v * v / float64(n)
+		}
+		assertAlmost(t, power(psd, rate/float64(n)), meanPower, 1e-9, fmt("power of %d samples", n))
+	}
+
+	_, err := signal.Periodogram(signal.Discrete{}, rate, nil)
+	assert(t, err != nil, "empty signal must fail")
+	_, err = signal.Periodogram(testSignal(8), 0, nil)
+	assert(t, err != nil, "invalid sample rate must fail")
+}
+
+func TestPeriodogramTone(t *testing.T) {
+	const rate = 8000.0
+
+	// 60Hz hum with amplitude 0.5 has power 0.125
+	sig := sine(60, rate, 8000)
+	for i := range sig {
+		sig[i] *= 0.5
+	}
+
+	psd, err := signal.Periodogram(sig, rate, signal.Hann)
+	assert(t, err == nil, fmt("periodogram: %v", err))
+
+	freqs := signal.FrequencyBins(len(sig), rate)
+	peak := 0
+	for k, v := range psd {
+		if v > psd[peak] {
+			peak = k
+		}
+	}
+	assertAlmost(t, freqs[peak], 60, precision, "peak frequency")
+	assertAlmost(t, power(psd, 1), 0.125, 1e-3, "tone power")
+}
+
+func TestWelch(t *testing.T) {
+	const (
+		rate = 1000.0
+		σ    = 0.1
+	)
+
+	// the density of white noise is flat: 2σ²/samplerate
+	sig := noise(64000, σ)
+	expected := 2 * σ * σ / rate
+
+	periodogram, err := signal.Periodogram(sig, rate, nil)
+	assert(t, err == nil, fmt("periodogram: %v", err))
+	bartlett, err := signal.Bartlett(sig, rate, 256)
+	assert(t, err == nil, fmt("bartlett: %v", err))
+	welch, err := signal.Welch(sig, rate, 256, 128, nil)
+	assert(t, err == nil, fmt("welch: %v", err))
+
+	assert(t, len(bartlett) == 129 && len(welch) == 129, "number of bins")
+
+	// averaging lowers the variance of the estimate
+	spread := func(psd signal.Discrete) float64 {
+		return signal.StdDeviation(psd[1:len(psd)-1]) / expected
+	}
+	assert(t, spread(periodogram) > 0.5, fmt("periodogram spread: %f", spread(periodogram)))
+	assert(t, spread(bartlett) < 0.1, fmt("bartlett spread: %f", spread(bartlett)))
+	assert(t, spread(welch) < spread(bartlett), fmt("welch spread: %f", spread(welch)))
+
+	for _, psd := range []signal.Discrete{bartlett, welch} {
+		assertAlmost(t, signal.Mean(psd[1:len(psd)-1]), expected, expected/20, "white noise density")
+		assertAlmost(t, power(psd, rate/256), σ*σ, σ*σ/20, "white noise power")
+	}
+}
+
+func TestWelchInvalid(t *testing.T) {
+	sig := testSignal(100)
+
+	for _, tc := range []struct {
+		segment, overlap int
+	}{
+		{0, 0},
+		{101, 0},
+		{50, 50},
+		{50, -1},
+		{2, 0}, // Hann window of 2 samples has zero energy
+	} {
+		_, err := signal.Welch(sig, 1000, tc.segment, tc.overlap, nil)
+		assert(t, err != nil, fmt("segment[%d] overlap[%d] must fail", tc.segment, tc.overlap))
+	}
+
+	_, err := signal.Welch(sig, 0, 10, 5, nil)
+	assert(t, err != nil, "invalid sample rate must fail")
+
+	_, err = signal.Periodogram(sig[:2], 1000, signal.Hann)
+	assert(t, err != nil, "zero energy window must fail")
+}
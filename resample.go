@@ -0,0 +1,187 @@
+package signal
+
+import (
+	"fmt"
+	"math"
+)
+
+// Quality of the resampling, trading the attenuation and the width of
+// the transition band of the anti-aliasing filter for speed.
+type Quality int
+
+const (
+	// QualityLow uses a short filter, for previews and speech.
+	QualityLow Quality = iota
+	// QualityMedium is good enough for most audio.
+	QualityMedium
+	// QualityHigh uses a long filter with a narrow transition band and
+	// more than 100dB of attenuation.
+	QualityHigh
+)
+
+// qualityParams are the zero crossings of the sinc at each side of the
+// kernel, the β of the Kaiser window and the cutoff as a fraction of
+// the lower Nyquist frequency.
+var qualityParams = map[Quality]struct {
+	zeros   int
+	β       float64
+	rolloff float64
+}{
+	QualityLow:    {zeros: 4, β: 5, rolloff: 0.85},
+	QualityMedium: {zeros: 16, β: 8, rolloff: 0.9},
+	QualityHigh:   {zeros: 32, β: 10, rolloff: 0.95},
+}
+
+// Resampler converts the sample rate of a signal by the rational ratio
+// up/down, using a polyphase windowed-sinc filter: it's the same of
+// inserting up-1 zeros between the samples, low-pass filtering and
+// keeping one of every down samples, but only the nonzero products
+// are calculated.
+// It processes a signal in blocks, carrying its state across calls,
+// such that the output is delayed by Delay input samples.
+type Resampler struct {
+	up, down int
+	phases   []Discrete // phases[p][t] = h(p + t·up)
+	history  Discrete   // last samples of the previous blocks
+	next     int        // index of the next output in the upsampled block
+}
+
+// NewResampler creates a resampler from the sample rate from to the
+// sample rate to, in hertz. The rates are rounded to whole hertz and
+// the ratio is reduced, eg. 44100Hz to 48000Hz is 160/147. Note that
+// the size of the filter grows with the reduced up factor.
+func NewResampler(from, to float64, quality Quality) (*Resampler, error) {
+	qp, ok := qualityParams[quality]
+	if !ok {
+		return nil, fmt.Errorf("unknown resampling quality: %d", quality)
+	}
+	if from < 1 || to < 1 {
+		return nil, fmt.Errorf("invalid sample rates: from[%f] to[%f]", from, to)
+	}
+
+	up, down := int(math.Floor(to+0.5)), int(math.Floor(from+0.5))
+	g := gcd(up, down)
+	up, down = up/g, down/g
+
+	// the filter runs at the upsampled rate, with the cutoff at the
+	// lower of the Nyquist frequencies
+	factor := 1.0
+	if down > up {
+		factor = float64(down) / float64(up)
+	}
+	fc := qp.rolloff / (2 * float64(up) * factor)
+
+	taps := 2 * int(math.Ceil(float64(qp.zeros)*factor))
+	n := taps*up + 1
+	center := float64(n-1) / 2
+	window := Kaiser(n, qp.β)
+
+	phases := make([]Discrete, up)
+	for p := range phases {
+		phases[p] = make(Discrete, taps+1)
+		for t := range phases[p] {
+			i := p + t*up
+			if i >= n {
+				continue
+			}
+			// the gain of up compensates the inserted zeros
+			phases[p][t] = float64(up) * 2 * fc * sinc(2*fc*(float64(i)-center)) * window[i]
+		}
+	}
+
+	r := &Resampler{
+		up:     up,
+		down:   down,
+		phases: phases,
+	}
+	r.Reset()
+	return r, nil
+}
+
+// Ratio returns the reduced conversion ratio up/down.
+func (r *Resampler) Ratio() (up, down int) {
+	return r.up, r.down
+}
+
+// Delay returns the delay of the output, in input samples.
+func (r *Resampler) Delay() float64 {
+	return float64(len(r.history)) / 2
+}
+
+// Process resamples the block, returning about len(block)·up/down
+// samples (the exact number depends on the previous blocks).
+func (r *Resampler) Process(block Discrete) Discrete {
+	h := len(r.history)
+
+	input := make(Discrete, 0, h+len(block))
+	input = append(input, r.history...)
+	input = append(input, block...)
+
+	var out Discrete
+	for ; r.next/r.up < len(block); r.next += r.down {
+		phase := r.phases[r.next%r.up]
+		base := r.next/r.up + h
+
+		var sum float64
+		for t, c := range phase {
+			sum += c * input[base-t]
+		}
+		out = append(out, sum)
+	}
+
+	r.next -= len(block) * r.up
+	copy(r.history, input[len(input)-h:])
+	return out
+}
+
+// Reset clears the state of the resampler.
+func (r *Resampler) Reset() {
+	r.history = make(Discrete, len(r.phases[0])-1)
+	r.next = 0
+}
+
+// Resample converts the sample rate of sig from the rate from to the
+// rate to (see NewResampler). Unlike the Resampler, the output isn't
+// delayed and has ceil(len(sig)·up/down) samples.
+func Resample(sig Discrete, from, to float64, quality Quality) (Discrete, error) {
+	r, err := NewResampler(from, to, quality)
+	if err != nil {
+		return nil, err
+	}
+
+	n := (len(sig)*r.up + r.down - 1) / r.down
+	delay := len(r.history) / 2
+
+	// starts at the center of the kernel, and pushes the last samples
+	// out of the filter with zeros
+	r.next = delay * r.up
+	padded := make(Discrete, len(sig)+delay+1)
+	copy(padded, sig)
+
+	return r.Process(padded)[:n], nil
+}
+
+// Resample converts the sample rate of every channel of m to
+// samplerate (see Resample).
+func (m Multichannel) Resample(samplerate float64, quality Quality) (Multichannel, error) {
+	out := Multichannel{
+		SampleRate: samplerate,
+		Channels:   make([]Discrete, len(m.Channels)),
+	}
+
+	for i, ch := range m.Channels {
+		resampled, err := Resample(ch, m.SampleRate, samplerate, quality)
+		if err != nil {
+			return Multichannel{}, err
+		}
+		out.Channels[i] = resampled
+	}
+	return out, nil
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
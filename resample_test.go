@@ -0,0 +1,128 @@
+package signal_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/NeowayLabs/signal"
+)
+
+func sine(freq, samplerate float64, n int) signal.Discrete {
+	sig, _ := signal.SampleN(func(t float64) float64 {
+		return math.Sin(2 * math.Pi * freq * t)
+	}, samplerate, 0, n)
+	return sig
+}
+
+func TestResamplerRatio(t *testing.T) {
+	for _, tc := range []struct {
+		from, to float64
+		up, down int
+	}{
+		{8000, 16000, 2, 1},
+		{48000, 8000, 1, 6},
+		{44100, 48000, 160, 147},
+		{48000, 44100, 147, 160},
+		{16000, 16000, 1, 1},
+	} {
+		r, err := signal.NewResampler(tc.from, tc.to, signal.QualityLow)
+		assert(t, err == nil, fmt("new resampler: %v", err))
+
+		up, down := r.Ratio()
+		assert(t, up == tc.up && down == tc.down,
+			fmt("%f -> %f: ratio %d/%d != %d/%d", tc.from, tc.to, up, down, tc.up, tc.down))
+	}
+
+	_, err := signal.NewResampler(0, 8000, signal.QualityLow)
+	assert(t, err != nil, "invalid rate must fail")
+	_, err = signal.NewResampler(8000, 16000, signal.Quality(42))
+	assert(t, err != nil, "unknown quality must fail")
+}
+
+func TestResample(t *testing.T) {
+	const freq = 1000.0
+
+	for _, tc := range []struct {
+		from, to float64
+	}{
+		{8000, 16000},
+		{16000, 8000},
+		{44100, 48000},
+		{48000, 44100},
+		{8000, 44100},
+	} {
+		for _, q := range []signal.Quality{signal.QualityLow, signal.QualityMedium, signal.QualityHigh} {
+			sig := sine(freq, tc.from, int(tc.from/10))
+
+			out, err := signal.Resample(sig, tc.from, tc.to, q)
+			assert(t, err == nil, fmt("resample: %v", err))
+			assert(t, len(out) == int(tc.to/10),
+				fmt("%f -> %f: unexpected length %d", tc.from, tc.to, len(out)))
+
+			// far from the edges, the output is the tone sampled at
+			// the new rate
+			expected := sine(freq, tc.to, len(out))
+			ε := 1e-2
+			if q == signal.QualityLow {
+				ε = 5e-2
+			}
+			for i := len(out) / 4; i < 3*len(out)/4; i++ {
+				assertAlmost(t, out[i], expected[i], ε,
+					fmt("%f -> %f (quality %d): sample %d", tc.from, tc.to, q, i))
+			}
+		}
+	}
+}
+
+func TestResampleAntiAliasing(t *testing.T) {
+	// 6kHz is above the Nyquist frequency of 8kHz
+	sig := sine(6000, 48000, 4800)
+
+	out, err := signal.Resample(sig, 48000, 8000, signal.QualityHigh)
+	assert(t, err == nil, fmt("resample: %v", err))
+
+	for i := len(out) / 4; i < 3*len(out)/4; i++ {
+		assertAlmost(t, out[i], 0, 1e-3, fmt("aliased sample %d", i))
+	}
+}
+
+func TestResamplerStreaming(t *testing.T) {
+	sig := testSignal(1000)
+
+	whole, err := signal.NewResampler(44100, 48000, signal.QualityMedium)
+	assert(t, err == nil, fmt("new resampler: %v", err))
+	expected := whole.Process(sig)
+
+	blocks, err := signal.NewResampler(44100, 48000, signal.QualityMedium)
+	assert(t, err == nil, fmt("new resampler: %v", err))
+
+	var got signal.Discrete
+	for start := 0; start < len(sig); start += 77 {
+		end := start + 77
+		if end > len(sig) {
+			end = len(sig)
+		}
+		got = append(got, blocks.Process(sig[start:end])...)
+	}
+	assertDiscreteAlmost(t, got, expected, precision, "streaming")
+
+	blocks.Reset()
+	assertDiscreteAlmost(t, blocks.Process(sig), expected, precision, "after reset")
+}
+
+func TestResampleMultichannel(t *testing.T) {
+	m := signal.Multichannel{
+		SampleRate: 16000,
+		Channels: []signal.Discrete{
+			sine(500, 16000, 1600),
+			sine(1500, 16000, 1600),
+		},
+	}
+
+	out, err := m.Resample(8000, signal.QualityMedium)
+	assert(t, err == nil, fmt("resample: %v", err))
+	assert(t, out.SampleRate == 8000, fmt("sample rate: %f", out.SampleRate))
+	assert(t, out.NumChannels() == 2, fmt("channels: %d", out.NumChannels()))
+	assert(t, out.NumFrames() == 800, fmt("frames: %d", out.NumFrames()))
+	assertAlmost(t, out.Duration(), m.Duration(), precision, "duration")
+}
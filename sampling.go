@@ -0,0 +1,142 @@
+package signal
+
+import (
+	"fmt"
+	"math"
+)
+
+// Interpolation method used to reconstruct a continuous signal from
+// its samples.
+type Interpolation int
+
+const (
+	// InterpLinear joins the samples with straight lines.
+	InterpLinear Interpolation = iota
+	// InterpCubic joins the samples with Catmull-Rom cubic splines,
+	// that are smooth at the samples.
+	InterpCubic
+	// InterpSinc is the ideal band-limited reconstruction
+	// (Whittaker-Shannon interpolation, DSP Book, Chapter 3), that
+	// sums a sinc function centered at every sample. It's exact for
+	// signals sampled above the Nyquist rate, but costs O(N) for each
+	// value.
+	InterpSinc
+)
+
+// Sample samples the continuous signal f at samplerate hertz, starting
+// at start seconds and lasting duration seconds. The sample i is
+// f(start + i/samplerate).
+func Sample(f Continuous, samplerate, start, duration float64) (Discrete, error) {
+	if duration < 0 {
+		return nil, fmt.Errorf("invalid duration: %f", duration)
+	}
+	return SampleN(f, samplerate, start, int(math.Floor(duration*samplerate+0.5)))
+}
+
+// SampleN takes n samples of the continuous signal f at samplerate
+// hertz, starting at start seconds.
+func SampleN(f Continuous, samplerate, start float64, n int) (Discrete, error) {
+	if samplerate <= 0 {
+		return nil, fmt.Errorf("invalid sample rate: %f", samplerate)
+	}
+	if n < 0 {
+		return nil, fmt.Errorf("invalid number of samples: %d", n)
+	}
+
+	sig := make(Discrete, n)
+	for i := range sig {
+		sig[i] = f(start + float64(i)/samplerate)
+	}
+	return sig, nil
+}
+
+// Reconstruct returns a continuous signal that passes through the
+// samples of sig, taken at samplerate hertz starting at start seconds,
+// using the interp method. The signal is zero outside of the sampled
+// interval, except for the sinc interpolation that is defined
+// everywhere.
+func Reconstruct(sig Discrete, samplerate, start float64, interp Interpolation) (Continuous, error) {
+	if samplerate <= 0 {
+		return nil, fmt.Errorf("invalid sample rate: %f", samplerate)
+	}
+
+	// position in samples
+	pos := func(t float64) float64 {
+		return (t - start) * samplerate
+	}
+
+	switch interp {
+	case InterpLinear:
+		return func(t float64) float64 {
+			x := pos(t)
+			i, ok := sampleIndex(len(sig), x)
+			if !ok {
+				return 0
+			}
+			if i == len(sig)-1 {
+				return sig[i]
+			}
+			frac := x - float64(i)
+			return sig[i] + frac*(sig[i+1]-sig[i])
+		}, nil
+	case InterpCubic:
+		return func(t float64) float64 {
+			x := pos(t)
+			i, ok := sampleIndex(len(sig), x)
+			if !ok {
+				return 0
+			}
+			at := func(j int) float64 {
+				// extrapolates the neighbours at the edges linearly
+				n := len(sig)
+				switch {
+				case n == 1:
+					return sig[0]
+				case j < 0:
+					return 2*sig[0] - sig[1]
+				case j >= n:
+					return 2*sig[n-1] - sig[n-2]
+				}
+				return sig[j]
+			}
+			return catmullRom(at(i-1), at(i), at(i+1), at(i+2), x-float64(i))
+		}, nil
+	case InterpSinc:
+		return func(t float64) float64 {
+			x := pos(t)
+			var sum float64
+			for i, v := range sig {
+				sum += v * sinc(x-float64(i))
+			}
+			return sum
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown interpolation: %d", interp)
+}
+
+// sampleIndex returns the index of the sample at or before the
+// position x (in samples), or false if x is outside of the n samples.
+func sampleIndex(n int, x float64) (int, bool) {
+	if n == 0 || x < 0 || x > float64(n-1) || math.IsNaN(x) {
+		return 0, false
+	}
+	return int(x), true
+}
+
+// catmullRom interpolates between p1 and p2 at the fraction t, using
+// the neighbours p0 and p3 to estimate the slopes.
+func catmullRom(p0, p1, p2, p3, t float64) float64 {
+	a := -0.5*p0 + 1.5*p1 - 1.5*p2 + 0.5*p3
+	b := p0 - 2.5*p1 + 2*p2 - 0.5*p3
+	c := -0.5*p0 + 0.5*p2
+	return ((a*t+b)*t+c)*t + p1
+}
+
+// sinc is the normalized sinc function: sin(πx)/(πx).
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	return math.Sin(math.Pi*x) / (math.Pi * x)
+}
@@ -0,0 +1,117 @@
+package signal_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/NeowayLabs/signal"
+)
+
+func TestSample(t *testing.T) {
+	line := func(t float64) float64 { return 2*t + 1 }
+
+	sig, err := signal.Sample(line, 10, 1, 0.5)
+	assert(t, err == nil, fmt("sample: %v", err))
+	assert(t, len(sig) == 5, fmt("number of samples: %d", len(sig)))
+	for i, v := range sig {
+		assertAlmost(t, v, line(1+float64(i)/10), precision, fmt("sample %d", i))
+	}
+
+	sig, err = signal.SampleN(line, 10, 0, 3)
+	assert(t, err == nil, fmt("sample n: %v", err))
+	assert(t, len(sig) == 3, fmt("number of samples: %d", len(sig)))
+
+	_, err = signal.Sample(line, 0, 0, 1)
+	assert(t, err != nil, "zero sample rate must fail")
+	_, err = signal.Sample(line, 10, 0, -1)
+	assert(t, err != nil, "negative duration must fail")
+	_, err = signal.SampleN(line, 10, 0, -1)
+	assert(t, err != nil, "negative number of samples must fail")
+}
+
+func TestSampleAliasing(t *testing.T) {
+	const rate = 4000.0
+
+	// a 3kHz sine sampled at 4kHz is not distinguishable from a
+	// (phase inverted) 1kHz sine.
+	tone := func(f float64) signal.Continuous {
+		return func(t float64) float64 {
+			return math.Sin(2 * math.Pi * f * t)
+		}
+	}
+
+	high, err := signal.SampleN(tone(3000), rate, 0, 64)
+	assert(t, err == nil, fmt("sample: %v", err))
+	alias, err := signal.SampleN(tone(-1000), rate, 0, 64)
+	assert(t, err == nil, fmt("sample: %v", err))
+
+	for i := range high {
+		assertAlmost(t, high[i], alias[i], 1e-9, fmt("sample %d", i))
+	}
+}
+
+func TestReconstruct(t *testing.T) {
+	const rate = 100.0
+
+	for _, tc := range []struct {
+		name   string
+		interp signal.Interpolation
+		f      signal.Continuous
+		ε      float64
+	}{
+		{
+			name:   "linear",
+			interp: signal.InterpLinear,
+			f:      func(t float64) float64 { return 3*t - 1 },
+			ε:      precision,
+		},
+		{
+			name:   "cubic",
+			interp: signal.InterpCubic,
+			f:      func(t float64) float64 { return t*t - t },
+			ε:      precision,
+		},
+		{
+			name:   "sinc",
+			interp: signal.InterpSinc,
+			f:      func(t float64) float64 { return math.Sin(2 * math.Pi * 5 * t) },
+			ε:      1e-2,
+		},
+	} {
+		sig, err := signal.SampleN(tc.f, rate, 0, 200)
+		assert(t, err == nil, fmt("%s: sample: %v", tc.name, err))
+
+		g, err := signal.Reconstruct(sig, rate, 0, tc.interp)
+		assert(t, err == nil, fmt("%s: reconstruct: %v", tc.name, err))
+
+		// at the samples
+		for i := 0; i < len(sig); i += 17 {
+			ti := float64(i) / rate
+			assertAlmost(t, g(ti), sig[i], precision, fmt("%s: at sample %d", tc.name, i))
+		}
+
+		// between the samples, far from the edges
+		for ti := 0.5; ti < 1.5; ti += 0.0123 {
+			assertAlmost(t, g(ti), tc.f(ti), tc.ε, fmt("%s: at %f", tc.name, ti))
+		}
+	}
+}
+
+func TestReconstructOutside(t *testing.T) {
+	sig := signal.Discrete{1, 2, 3}
+
+	for _, interp := range []signal.Interpolation{signal.InterpLinear, signal.InterpCubic} {
+		g, err := signal.Reconstruct(sig, 1, 10, interp)
+		assert(t, err == nil, fmt("reconstruct: %v", err))
+
+		assertAlmost(t, g(9.5), 0, precision, "before the samples")
+		assertAlmost(t, g(12.5), 0, precision, "after the samples")
+		assertAlmost(t, g(12), 3, precision, "last sample")
+		assertAlmost(t, g(10.5), 1.5, precision, "between the samples")
+	}
+
+	_, err := signal.Reconstruct(sig, 0, 0, signal.InterpLinear)
+	assert(t, err != nil, "zero sample rate must fail")
+	_, err = signal.Reconstruct(sig, 1, 0, signal.Interpolation(42))
+	assert(t, err != nil, "unknown interpolation must fail")
+}
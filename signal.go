@@ -34,7 +34,7 @@ func Mean(sig Discrete) float64 {
 // StdDeviation returns the standard deviation of the signal
 // (Represented as σ in DSP formulas).
 func StdDeviation(sig Discrete) float64 {
-	return StdDeviation2(sig, Mean(sig))
+	return math.Sqrt(Variance(sig))
 }
 
 // StdDeviation2 returns the standatd deviation from mean μ.
@@ -43,12 +43,10 @@ func StdDeviation2(sig Discrete, μ float64) float64 {
 }
 
 // Variance is the power of the standard deviation σ (represented by σ² in DSP).
-// TODO: Call to this function result in excessive round-off errors because
-// of the substracting of very close values (x(i)-μ).
-// DSP Book, Chapter 2, pag. 16, shows an alternate method called
-// 'running statistics' that should be used instead.
+// It's calculated with running statistics (see RunningStats) to avoid
+// the round-off errors of subtracting very close values (x(i)-μ).
 func Variance(sig Discrete) float64 {
-	return Variance2(sig, Mean(sig))
+	return Stats(sig).Variance()
 }
 
 // Variance2 is the variance from the mean μ
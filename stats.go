@@ -0,0 +1,140 @@
+package signal
+
+import "math"
+
+// RunningStats accumulates the statistics of a signal one sample at
+// a time (DSP Book, Chapter 2, 'running statistics'), using the
+// Welford's method that avoids the round-off errors of subtracting
+// very close values. It could be used over a whole signal or updated
+// with the blocks of a stream.
+// The zero value is ready to use.
+type RunningStats struct {
+	n          uint64
+	mean       float64
+	m2, m3, m4 float64 // sums of powers of differences from the mean
+	min, max   float64
+}
+
+// Stats calculates the statistics of the whole signal sig.
+func Stats(sig Discrete) RunningStats {
+	var r RunningStats
+	r.AddSignal(sig)
+	return r
+}
+
+// Add accumulates the sample x.
+func (r *RunningStats) Add(x float64) {
+	n1 := float64(r.n)
+	r.n++
+	n := float64(r.n)
+
+	δ := x - r.mean
+	δn := δ / n
+	δn2 := δn * δn
+	term := δ * δn * n1
+
+	r.mean += δn
+	r.m4 += term*δn2*(n*n-3*n+3) + 6*δn2*r.m2 - 4*δn*r.m3
+	r.m3 += term*δn*(n-2) - 3*δn*r.m2
+	r.m2 += term
+
+	if r.n == 1 || x < r.min {
+		r.min = x
+	}
+	if r.n == 1 || x > r.max {
+		r.max = x
+	}
+}
+
+// AddSignal accumulates every sample of sig.
+func (r *RunningStats) AddSignal(sig Discrete) {
+	for _, v := range sig {
+		r.Add(v)
+	}
+}
+
+// Merge accumulates the statistics of other, as if all of its samples
+// were added to r.
+func (r *RunningStats) Merge(other RunningStats) {
+	if other.n == 0 {
+		return
+	}
+	if r.n == 0 {
+		*r = other
+		return
+	}
+
+	na, nb := float64(r.n), float64(other.n)
+	n := na + nb
+
+	δ := other.mean - r.mean
+	δ2 := δ * δ
+	δ3 := δ2 * δ
+	δ4 := δ2 * δ2
+
+	m2 := r.m2 + other.m2 + δ2*na*nb/n
+	m3 := r.m3 + other.m3 + δ3*na*nb*(na-nb)/(n*n) +
+		3*δ*(na*other.m2-nb*r.m2)/n
+	m4 := r.m4 + other.m4 + δ4*na*nb*(na*na-na*nb+nb*nb)/(n*n*n) +
+		6*δ2*(na*na*other.m2+nb*nb*r.m2)/(n*n) +
+		4*δ*(na*other.m3-nb*r.m3)/n
+
+	r.mean = (na*r.mean + nb*other.mean) / n
+	r.m2, r.m3, r.m4 = m2, m3, m4
+	r.n += other.n
+	r.min = math.Min(r.min, other.min)
+	r.max = math.Max(r.max, other.max)
+}
+
+// Count returns the number of samples accumulated.
+func (r RunningStats) Count() uint64 {
+	return r.n
+}
+
+// Mean returns the mean (μ) of the samples.
+func (r RunningStats) Mean() float64 {
+	return r.mean
+}
+
+// Variance returns the variance (σ²) of the samples, using N-1 as the
+// divisor like Variance2.
+func (r RunningStats) Variance() float64 {
+	if r.n < 2 {
+		return 0
+	}
+	return r.m2 / float64(r.n-1)
+}
+
+// StdDeviation returns the standard deviation (σ) of the samples.
+func (r RunningStats) StdDeviation() float64 {
+	return math.Sqrt(r.Variance())
+}
+
+// Min returns the smallest sample.
+func (r RunningStats) Min() float64 {
+	return r.min
+}
+
+// Max returns the biggest sample.
+func (r RunningStats) Max() float64 {
+	return r.max
+}
+
+// Skewness returns the skewness of the samples, a measure of the
+// asymmetry of the distribution (zero for symmetric distributions).
+func (r RunningStats) Skewness() float64 {
+	if r.m2 == 0 {
+		return 0
+	}
+	return math.Sqrt(float64(r.n)) * r.m3 / math.Pow(r.m2, 1.5)
+}
+
+// Kurtosis returns the excess kurtosis of the samples, a measure of
+// the tailedness of the distribution (zero for the normal
+// distribution).
+func (r RunningStats) Kurtosis() float64 {
+	if r.m2 == 0 {
+		return 0
+	}
+	return float64(r.n)*r.m4/(r.m2*r.m2) - 3
+}
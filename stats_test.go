@@ -0,0 +1,70 @@
+package signal_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/NeowayLabs/signal"
+)
+
+func TestRunningStats(t *testing.T) {
+	for _, tc := range testcases {
+		r := signal.Stats(tc.sig)
+		assert(t, r.Count() == uint64(len(tc.sig)), "count")
+		assertAlmost(t, r.Mean(), tc.μ, precision, "running mean")
+		assertAlmost(t, r.StdDeviation(), tc.σ, precision, "running std deviation")
+		assertAlmost(t, r.Variance(), tc.σ2, precision, "running variance")
+	}
+}
+
+func TestRunningStatsMoments(t *testing.T) {
+	sig := signal.Discrete{2, 8, 0, 4, 1, 9, 9, 0}
+	r := signal.Stats(sig)
+
+	assertAlmost(t, r.Min(), 0, 0, "min")
+	assertAlmost(t, r.Max(), 9, 0, "max")
+	assertAlmost(t, r.Mean(), 4.125, precision, "mean")
+
+	// population moments calculated directly
+	var m2, m3, m4 float64
+	for _, v := range sig {
+		d := v - r.Mean()
+		m2 += d * d
+		m3 += d * d * d
+		m4 += d * d * d * d
+	}
+	n := float64(len(sig))
+	skewness := math.Sqrt(n) * m3 / math.Pow(m2, 1.5)
+	kurtosis := n*m4/(m2*m2) - 3
+
+	assertAlmost(t, r.Skewness(), skewness, precision, "skewness")
+	assertAlmost(t, r.Kurtosis(), kurtosis, precision, "kurtosis")
+}
+
+func TestRunningStatsMerge(t *testing.T) {
+	sig := testSignal(1000)
+	whole := signal.Stats(sig)
+
+	var merged signal.RunningStats
+	for start := 0; start < len(sig); start += 137 {
+		end := start + 137
+		if end > len(sig) {
+			end = len(sig)
+		}
+		merged.Merge(signal.Stats(sig[start:end]))
+	}
+
+	assert(t, merged.Count() == whole.Count(), "merged count")
+	assertAlmost(t, merged.Mean(), whole.Mean(), 1e-12, "merged mean")
+	assertAlmost(t, merged.Variance(), whole.Variance(), 1e-12, "merged variance")
+	assertAlmost(t, merged.Skewness(), whole.Skewness(), 1e-9, "merged skewness")
+	assertAlmost(t, merged.Kurtosis(), whole.Kurtosis(), 1e-9, "merged kurtosis")
+	assertAlmost(t, merged.Min(), whole.Min(), 0, "merged min")
+	assertAlmost(t, merged.Max(), whole.Max(), 0, "merged max")
+}
+
+func TestVarianceRoundOff(t *testing.T) {
+	// small variations over a huge offset
+	sig := signal.Discrete{1e9 + 4, 1e9 + 7, 1e9 + 13, 1e9 + 16}
+	assertAlmost(t, signal.Variance(sig), 30, precision, "variance with offset")
+}
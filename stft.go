@@ -0,0 +1,180 @@
+package signal
+
+import (
+	"fmt"
+	"math"
+)
+
+type (
+	// STFT are the parameters of the short-time Fourier transform,
+	// that calculates the spectrum of overlapping frames of a signal
+	// to show how the spectrum changes over time.
+	STFT struct {
+		FrameSize  int     // samples per frame
+		Hop        int     // samples between the start of the frames
+		FFTSize    int     // frame size zero-padded, zero uses FrameSize
+		Window     Window  // window of the frames, nil uses Hann
+		SampleRate float64 // in hertz
+	}
+
+	// Spectrogram is the time × frequency result of a STFT. The frame
+	// t is centered at the sample t·Hop of the signal, that is padded
+	// with zeros at both ends.
+	Spectrogram struct {
+		STFT
+
+		Frames [][]complex128 // Frames[t][k] is the bin k of the frame t
+		Length int            // number of samples of the signal
+	}
+)
+
+// NewSTFT creates the STFT parameters with the Hann window and no
+// zero-padding.
+func NewSTFT(framesize, hop int, samplerate float64) STFT {
+	return STFT{
+		FrameSize:  framesize,
+		Hop:        hop,
+		FFTSize:    framesize,
+		Window:     Hann,
+		SampleRate: samplerate,
+	}
+}
+
+// Transform calculates the STFT of sig. Each frame has FFTSize/2+1
+// bins, as returned by RealFFT.
+func (s STFT) Transform(sig Discrete) (*Spectrogram, error) {
+	s, err := s.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	window := s.Window(s.FrameSize)
+	nframes := 0
+	if len(sig) > 0 {
+		nframes = (len(sig)-1+s.Hop-1)/s.Hop + 1
+	}
+
+	frames := make([][]complex128, nframes)
+	frame := make(Discrete, s.FFTSize)
+	for t := range frames {
+		start := t*s.Hop - s.FrameSize/2
+		for i := range frame {
+			frame[i] = 0
+			if j := start + i; i < s.FrameSize && j >= 0 && j < len(sig) {
+				frame[i] = sig[j] * window[i]
+			}
+		}
+		frames[t] = RealFFT(frame)
+	}
+
+	return &Spectrogram{
+		STFT:   s,
+		Frames: frames,
+		Length: len(sig),
+	}, nil
+}
+
+// Inverse calculates the signal back from the spectrogram, by the
+// weighted overlap-add of the inverse transform of the frames (ISTFT).
+// The frames are windowed again and the sum is normalized by the sum
+// of the squared windows, such that an unchanged spectrogram gives the
+// original signal, as long as the frames overlap enough for the
+// windows to cover every sample.
+func (sp *Spectrogram) Inverse() (Discrete, error) {
+	s, err := sp.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	window := s.Window(s.FrameSize)
+	out := make(Discrete, sp.Length)
+	norm := make(Discrete, sp.Length)
+
+	for t, X := range sp.Frames {
+		if len(X) != s.FFTSize/2+1 {
+			return nil, fmt.Errorf("frame[%d] has %d bins, expected %d",
+				t, len(X), s.FFTSize/2+1)
+		}
+
+		frame, err := InverseRealFFT(X, s.FFTSize)
+		if err != nil {
+			return nil, err
+		}
+		start := t*s.Hop - s.FrameSize/2
+		for i := 0; i < s.FrameSize; i++ {
+			j := start + i
+			if j < 0 || j >= len(out) {
+				continue
+			}
+			out[j] += frame[i] * window[i]
+			norm[j] += window[i] * window[i]
+		}
+	}
+
+	for i := range out {
+		if norm[i] > 1e-12 {
+			out[i] /= norm[i]
+		}
+	}
+	return out, nil
+}
+
+// Magnitudes returns the magnitude of every bin of every frame.
+func (sp *Spectrogram) Magnitudes() []Discrete {
+	out := make([]Discrete, len(sp.Frames))
+	for t, X := range sp.Frames {
+		out[t] = Magnitude(X)
+	}
+	return out
+}
+
+// Decibels returns the magnitude of every bin in decibels
+// (20·log10|X|), limited below by floor dB to avoid the -Inf of the
+// empty bins.
+func (sp *Spectrogram) Decibels(floor float64) []Discrete {
+	out := sp.Magnitudes()
+	for _, frame := range out {
+		for k, v := range frame {
+			frame[k] = math.Max(20*math.Log10(v), floor)
+		}
+	}
+	return out
+}
+
+// Times returns the time (in seconds) of the center of each frame.
+func (sp *Spectrogram) Times() Discrete {
+	out := make(Discrete, len(sp.Frames))
+	for t := range out {
+		out[t] = float64(t*sp.Hop) / sp.SampleRate
+	}
+	return out
+}
+
+// Frequencies returns the frequency (in hertz) of each bin.
+func (sp *Spectrogram) Frequencies() Discrete {
+	return FrequencyBins(sp.FFTSize, sp.SampleRate)
+}
+
+// validate checks the parameters, filling the defaults.
+func (s STFT) validate() (STFT, error) {
+	if s.FrameSize <= 0 {
+		return s, fmt.Errorf("invalid frame size: %d", s.FrameSize)
+	}
+	if s.Hop <= 0 {
+		return s, fmt.Errorf("invalid hop: %d", s.Hop)
+	}
+	if s.SampleRate <= 0 {
+		return s, fmt.Errorf("invalid sample rate: %f", s.SampleRate)
+	}
+	if s.FFTSize == 0 {
+		s.FFTSize = s.FrameSize
+	}
+	if s.FFTSize < s.FrameSize {
+		return s, fmt.Errorf("FFT size[%d] is smaller than the frame size[%d]",
+			s.FFTSize, s.FrameSize)
+	}
+	if s.Window == nil {
+		s.Window = Hann
+	}
+	return s, nil
+}
@@ -0,0 +1,91 @@
+package signal_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/NeowayLabs/signal"
+)
+
+func TestSTFT(t *testing.T) {
+	const rate = 8000.0
+
+	// 500Hz in the first half and 2kHz in the second half
+	sig := append(sine(500, rate, 4000), sine(2000, rate, 4000)...)
+
+	sp, err := signal.NewSTFT(256, 128, rate).Transform(sig)
+	assert(t, err == nil, fmt("stft: %v", err))
+
+	assert(t, len(sp.Frames) == 64, fmt("number of frames: %d", len(sp.Frames)))
+	assert(t, len(sp.Frames[0]) == 129, fmt("number of bins: %d", len(sp.Frames[0])))
+	assertAlmost(t, sp.Times()[1], 128/rate, precision, "time of frame")
+	assertAlmost(t, sp.Frequencies()[1], rate/256, precision, "frequency of bin")
+
+	freqs := sp.Frequencies()
+	peak := func(frame signal.Discrete) float64 {
+		max := 0
+		for k, v := range frame {
+			if v > frame[max] {
+				max = k
+			}
+		}
+		return freqs[max]
+	}
+
+	mags := sp.Magnitudes()
+	assertAlmost(t, peak(mags[10]), 500, rate/256, "first half")
+	assertAlmost(t, peak(mags[50]), 2000, rate/256, "second half")
+
+	dbs := sp.Decibels(-100)
+	for k, v := range mags[10] {
+		expected := math.Max(20*math.Log10(v), -100)
+		assertAlmost(t, dbs[10][k], expected, precision, "decibels")
+	}
+}
+
+func TestSTFTZeroPadding(t *testing.T) {
+	stft := signal.NewSTFT(100, 50, 1000)
+	stft.FFTSize = 512
+	stft.Window = signal.Hamming
+
+	sp, err := stft.Transform(testSignal(1000))
+	assert(t, err == nil, fmt("stft: %v", err))
+	assert(t, len(sp.Frames[0]) == 257, fmt("number of bins: %d", len(sp.Frames[0])))
+	assertAlmost(t, sp.Frequencies()[1], 1000.0/512, precision, "frequency of bin")
+
+	stft.FFTSize = 50
+	_, err = stft.Transform(testSignal(1000))
+	assert(t, err != nil, "FFT size smaller than the frame must fail")
+}
+
+func TestInverseSTFT(t *testing.T) {
+	sig := testSignal(1001)
+
+	for _, stft := range []signal.STFT{
+		signal.NewSTFT(64, 32, 8000),
+		signal.NewSTFT(256, 64, 8000),
+		{FrameSize: 100, Hop: 25, FFTSize: 128, Window: signal.Blackman, SampleRate: 8000},
+	} {
+		sp, err := stft.Transform(sig)
+		assert(t, err == nil, fmt("stft: %v", err))
+
+		got, err := sp.Inverse()
+		assert(t, err == nil, fmt("istft: %v", err))
+		assertDiscreteAlmost(t, got, sig, 1e-9, "inverse")
+	}
+}
+
+func TestSTFTInvalid(t *testing.T) {
+	for _, stft := range []signal.STFT{
+		signal.NewSTFT(0, 1, 8000),
+		signal.NewSTFT(64, 0, 8000),
+		signal.NewSTFT(64, 32, 0),
+	} {
+		_, err := stft.Transform(testSignal(100))
+		assert(t, err != nil, fmt("%+v must fail", stft))
+	}
+
+	sp, err := signal.NewSTFT(64, 32, 8000).Transform(signal.Discrete{})
+	assert(t, err == nil, fmt("stft: %v", err))
+	assert(t, len(sp.Frames) == 0, "empty signal has no frames")
+}
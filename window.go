@@ -0,0 +1,171 @@
+package signal
+
+import "math"
+
+// Window is a function generating a window of n samples.
+// Parameterized windows could be adapted with a closure, eg.
+// func(n int) Discrete { return Kaiser(n, 8.6) }.
+type Window func(n int) Discrete
+
+// Rectangular returns the rectangular window of n samples, ie. all
+// samples are 1.
+func Rectangular(n int) Discrete {
+	w := make(Discrete, n)
+	for i := range w {
+		w[i] = 1
+	}
+	return w
+}
+
+// Hann returns the Hann (raised cosine) window of n samples.
+func Hann(n int) Discrete {
+	return cosineWindow(n, 0.5, 0.5)
+}
+
+// Hamming returns the Hamming window of n samples
+// (DSP Book, Chapter 16).
+func Hamming(n int) Discrete {
+	return cosineWindow(n, 0.54, 0.46)
+}
+
+// Blackman returns the Blackman window of n samples
+// (DSP Book, Chapter 16).
+func Blackman(n int) Discrete {
+	return cosineWindow(n, 0.42, 0.5, 0.08)
+}
+
+// BlackmanHarris returns the 4-term Blackman-Harris window of n
+// samples, with sidelobes below -92dB.
+func BlackmanHarris(n int) Discrete {
+	return cosineWindow(n, 0.35875, 0.48829, 0.14128, 0.01168)
+}
+
+// FlatTop returns the flat top window of n samples, that has a very
+// small scalloping loss, being used to measure the amplitude of
+// sinusoids.
+func FlatTop(n int) Discrete {
+	return cosineWindow(n, 0.21557895, 0.41663158, 0.277263158, 0.083578947, 0.006947368)
+}
+
+// Kaiser returns the Kaiser window of n samples. The β parameter
+// trades the main lobe width for the sidelobe level (β = 0 is the
+// rectangular window, β ≈ 8.6 is similar to Blackman).
+func Kaiser(n int, β float64) Discrete {
+	w := make(Discrete, n)
+	if n == 1 {
+		w[0] = 1
+		return w
+	}
+
+	m := float64(n - 1)
+	denom := bessel0(β)
+	for i := range w {
+		r := 2*float64(i)/m - 1
+		w[i] = bessel0(β*math.Sqrt(1-r*r)) / denom
+	}
+	return w
+}
+
+// Tukey returns the Tukey (tapered cosine) window of n samples, where
+// α is the fraction of the window inside the cosine tapers (α = 0 is
+// the rectangular window and α = 1 is the Hann window).
+func Tukey(n int, α float64) Discrete {
+	if α <= 0 {
+		return Rectangular(n)
+	}
+	if α >= 1 {
+		return Hann(n)
+	}
+
+	w := make(Discrete, n)
+	if n == 1 {
+		w[0] = 1
+		return w
+	}
+
+	m := float64(n - 1)
+	edge := α * m / 2
+	for i := range w {
+		x := float64(i)
+		switch {
+		case x < edge:
+			w[i] = 0.5 * (1 - math.Cos(math.Pi*x/edge))
+		case x > m-edge:
+			w[i] = 0.5 * (1 - math.Cos(math.Pi*(m-x)/edge))
+		default:
+			w[i] = 1
+		}
+	}
+	return w
+}
+
+// ApplyWindow multiplies the signal sig by the window in place.
+// The window must have (at least) the length of sig.
+func ApplyWindow(sig, window Discrete) {
+	for i := range sig {
+		sig[i] *= window[i]
+	}
+}
+
+// CoherentGain returns the gain of the window for coherent signals
+// (sinusoids), ie. the mean of the window. Divide the amplitude of a
+// windowed spectrum by it to correct the amplitude of the tones.
+func CoherentGain(window Discrete) float64 {
+	return Mean(window)
+}
+
+// EnergyGain returns the gain of the window for the power of
+// incoherent signals (noise), ie. the mean of the squared window.
+// Divide the power of a windowed spectrum by it to correct the power
+// of broadband signals.
+func EnergyGain(window Discrete) float64 {
+	if len(window) == 0 {
+		return 0
+	}
+	return energy(window) / float64(len(window))
+}
+
+// ENBW returns the equivalent noise bandwidth of the window, in bins.
+func ENBW(window Discrete) float64 {
+	cg := CoherentGain(window)
+	if cg == 0 {
+		return 0
+	}
+	return EnergyGain(window) / (cg * cg)
+}
+
+// cosineWindow returns the generalized cosine window of n samples:
+// w(i) = a0 - a1·cos(2πi/(n-1)) + a2·cos(4πi/(n-1)) - ...
+func cosineWindow(n int, coeffs ...float64) Discrete {
+	w := make(Discrete, n)
+	if n == 1 {
+		w[0] = 1
+		return w
+	}
+
+	m := float64(n - 1)
+	for i := range w {
+		var sum float64
+		sign := 1.0
+		for k, a := range coeffs {
+			sum += sign * a * math.Cos(2*math.Pi*float64(k)*float64(i)/m)
+			sign = -sign
+		}
+		w[i] = sum
+	}
+	return w
+}
+
+// bessel0 calculates the zeroth order modified Bessel function of the
+// first kind, using its power series.
+func bessel0(x float64) float64 {
+	sum, term := 1.0, 1.0
+	for k := 1; k < 500; k++ {
+		term *= (x / (2 * float64(k))) * (x / (2 * float64(k)))
+		sum += term
+		if term < sum*1e-17 {
+			break
+		}
+	}
+	return sum
+}
@@ -0,0 +1,94 @@
+package signal_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/NeowayLabs/signal"
+)
+
+func TestWindowsShape(t *testing.T) {
+	const n = 65
+
+	for _, tc := range []struct {
+		name   string
+		window signal.Discrete
+		edge   float64
+	}{
+		{"rectangular", signal.Rectangular(n), 1},
+		{"hann", signal.Hann(n), 0},
+		{"hamming", signal.Hamming(n), 0.08},
+		{"blackman", signal.Blackman(n), 0},
+		{"blackman-harris", signal.BlackmanHarris(n), 6e-5},
+		{"flat top", signal.FlatTop(n), -0.000421053},
+		{"kaiser β=0", signal.Kaiser(n, 0), 1},
+		{"tukey α=0.5", signal.Tukey(n, 0.5), 0},
+	} {
+		w := tc.window
+		assert(t, len(w) == n, tc.name+": length")
+		assertAlmost(t, w[0], tc.edge, 1e-6, tc.name+": edge")
+		assertAlmost(t, w[n/2], 1, 1e-6, tc.name+": center")
+		for i := range w {
+			assertAlmost(t, w[i], w[n-1-i], 1e-12, tc.name+": symmetry")
+		}
+	}
+
+	assert(t, len(signal.Hann(0)) == 0, "empty window")
+	assertAlmost(t, signal.Hann(1)[0], 1, 0, "single sample window")
+}
+
+func TestKaiser(t *testing.T) {
+	w := signal.Kaiser(11, 5)
+	// I0(5) = 27.239871823604442
+	assertAlmost(t, w[0], 1/27.239871823604442, 1e-12, "kaiser edge")
+	assertAlmost(t, w[5], 1, 1e-12, "kaiser center")
+}
+
+func TestTukeyLimits(t *testing.T) {
+	assertDiscreteAlmost(t, signal.Tukey(16, 0), signal.Rectangular(16), 0, "tukey α=0")
+	assertDiscreteAlmost(t, signal.Tukey(16, 1), signal.Hann(16), 0, "tukey α=1")
+
+	w := signal.Tukey(101, 0.2)
+	for i := 10; i <= 90; i++ {
+		assertAlmost(t, w[i], 1, 1e-12, "tukey flat region")
+	}
+}
+
+func TestWindowGains(t *testing.T) {
+	const n = 4096
+
+	for _, tc := range []struct {
+		name   string
+		window signal.Discrete
+		cg     float64
+		enbw   float64
+	}{
+		{"rectangular", signal.Rectangular(n), 1, 1},
+		{"hann", signal.Hann(n), 0.5, 1.5},
+		{"hamming", signal.Hamming(n), 0.54, 1.36},
+		{"blackman", signal.Blackman(n), 0.42, 1.73},
+		{"flat top", signal.FlatTop(n), 0.2156, 3.77},
+	} {
+		assertAlmost(t, signal.CoherentGain(tc.window), tc.cg, 1e-3, tc.name+": coherent gain")
+		assertAlmost(t, signal.ENBW(tc.window), tc.enbw, 1e-2, tc.name+": ENBW")
+	}
+
+	assertAlmost(t, signal.EnergyGain(signal.Hann(n)), 0.375, 1e-3, "hann energy gain")
+}
+
+func TestApplyWindow(t *testing.T) {
+	sig := signal.Discrete{2, 2, 2, 2, 2}
+	signal.ApplyWindow(sig, signal.Hann(5))
+	assertDiscreteAlmost(t, sig, signal.Discrete{0, 1, 2, 1, 0}, 1e-12, "windowed")
+
+	// amplitude correction of a windowed tone
+	const n = 1024
+	tone := make(signal.Discrete, n)
+	for i := range tone {
+		tone[i] = 0.8 * math.Cos(2*math.Pi*64*float64(i)/n)
+	}
+	w := signal.Hann(n)
+	signal.ApplyWindow(tone, w)
+	amplitude := 2 * signal.Magnitude(signal.RealFFT(tone))[64] / n / signal.CoherentGain(w)
+	assertAlmost(t, amplitude, 0.8, 1e-3, "corrected amplitude")
+}